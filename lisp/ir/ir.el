@@ -129,6 +129,9 @@
                  (goto-if-not-nil jmp)
                  (goto-if-nil-else-pop jmp)
                  (goto-if-not-nil-else-pop jmp)
+                 (push-condition-case jmp byte-pushconditioncase)
+                 ;; - Handler instructions -
+                 (pop-handler op0 byte-pophandler)
                  ;; - Instructions with argument -
                  (call op1)
                  (stack-set op1)
@@ -32,7 +32,8 @@ func assembleInstr(as *Assembler, ins *ir.Instr) {
 		assembleLabel(as, ins)
 	case ir.Xgoto:
 		assembleXgoto(as, ins)
-	case ir.Jmp, ir.JmpNil, ir.JmpNotNil, ir.JmpNilElsePop, ir.JmpNotNilElsePop:
+	case ir.Jmp, ir.JmpNil, ir.JmpNotNil, ir.JmpNilElsePop, ir.JmpNotNilElsePop,
+		ir.PushConditionCase:
 		assembleJmp(as, ins)
 
 	default:
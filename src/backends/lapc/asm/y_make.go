@@ -73,6 +73,13 @@ func (cy *converterY) convertInstr(ins *ir.Instr) {
 		ins.Kind = ir.Label
 		cy.st.Discard(uint16(cy.st.Len() - depth - 1))
 
+	case ir.XhandlerLabel:
+		// Handler is entered with stack restored to the
+		// condition-case depth plus pushed error object.
+		cy.labels[ins.Data] = labelInfo{depth: cy.st.Len()}
+		ins.Kind = ir.Label
+		cy.st.Push()
+
 	case ir.XlambdaRet:
 		info := branchInfo{ins: ins, depth: cy.st.Len()}
 		cy.lambdaRets = append(cy.lambdaRets, info)
@@ -63,6 +63,17 @@ func compileDynCall(cl *Compiler, form *sexp.DynCall) {
 	cl.push().Call(len(form.Args), "%dyn-call")
 }
 
+func compileLambda(cl *Compiler, form *sexp.Lambda) {
+	if len(form.Captured) == 0 {
+		compileSym(cl, form.Fn.Name)
+		return
+	}
+	args := make([]sexp.Form, 0, len(form.Captured)+1)
+	args = append(args, sexp.Symbol{Val: form.Fn.Name})
+	args = append(args, form.Captured...)
+	call(cl, "apply-partially", args...)
+}
+
 func compileInstrCall(cl *Compiler, form *lapc.InstrCall) {
 	compileExprList(cl, form.Args)
 	cl.pushInstr(form.Instr)
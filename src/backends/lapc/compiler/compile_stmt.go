@@ -5,7 +5,6 @@ import (
 	"backends/lapc"
 	"backends/lapc/ir"
 	"cfg"
-	"magic_pkg/emacs/rt"
 	"sexp"
 	"vmm"
 )
@@ -18,11 +17,7 @@ func compileBlock(cl *Compiler, form sexp.Block) {
 
 func compileReturn(cl *Compiler, form *sexp.Return) {
 	if cl.innerLambdaRet.Kind == ir.XlambdaRetLabel {
-		compileExpr(cl, form.Results[0])
-		for i := 1; i < len(form.Results); i++ {
-			compileExpr(cl, form.Results[i])
-			cl.push().XvarSet(rt.RetVars[i])
-		}
+		compileResults(cl, form.Results)
 		cl.push().XlambdaRet(cl.innerLambdaRet)
 		return
 	}
@@ -33,15 +28,27 @@ func compileReturn(cl *Compiler, form *sexp.Return) {
 		cl.push().ConstRef(cl.cvec.InsertSym("nil"))
 		cl.push().Return()
 	} else {
-		compileExpr(cl, form.Results[0])
-		for i := 1; i < len(form.Results); i++ {
-			compileExpr(cl, form.Results[i])
-			cl.push().XvarSet(rt.RetVars[i])
-		}
+		compileResults(cl, form.Results)
 		cl.push().Return()
 	}
 }
 
+// compileResults pushes function return value.
+// Several results are packed: a pair of results is returned
+// as cons, more results are returned as a vector.
+// Single result of multi-valued function forwards
+// results of another call that are already packed.
+func compileResults(cl *Compiler, results []sexp.Form) {
+	switch len(results) {
+	case 1:
+		compileExpr(cl, results[0])
+	case 2:
+		compileCall(cl, "cons", results)
+	default:
+		compileCall(cl, "vector", results)
+	}
+}
+
 func compileIf(cl *Compiler, form *sexp.If) {
 	endifLabel := cl.unit.NewLabel("endif")
 	if sexp.IsEmptyForm(form.Else) {
@@ -177,7 +184,7 @@ func compileStructUpdate(cl *Compiler, form *sexp.StructUpdate) {
 			compileExpr(cl, form.Expr)
 			cl.push().SetCdr()
 		} else {
-			cl.pushN(ir.Instr{Kind: ir.Cdr}, form.Index-1)
+			cl.pushN(ir.Instr{Kind: ir.Cdr}, form.Index)
 			compileExpr(cl, form.Expr)
 			cl.push().SetCar()
 		}
@@ -213,3 +220,19 @@ func compileGoto(cl *Compiler, form *sexp.Goto) {
 func compileLabel(cl *Compiler, form *sexp.Label) {
 	cl.push().Label(cl.unit.NewUserLabel(form.Name))
 }
+
+func compileProtect(cl *Compiler, form *sexp.Protect) {
+	handlerLabel := cl.unit.NewHandlerLabel()
+	doneLabel := cl.unit.NewLabel("protect-done")
+
+	// "t" condition catches any signal.
+	compileSym(cl, "t")
+	cl.push().PushConditionCase(handlerLabel)
+	compileBlock(cl, form.Body)
+	cl.push().PopHandler()
+	cl.push().Jmp(doneLabel)
+
+	cl.push().Label(handlerLabel)
+	cl.push().XlocalSet(form.ErrVar)
+	cl.push().Label(doneLabel)
+}
@@ -42,6 +42,8 @@ func compileStmt(cl *Compiler, form sexp.Form) {
 		compileGoto(cl, form)
 	case *sexp.Label:
 		compileLabel(cl, form)
+	case *sexp.Protect:
+		compileProtect(cl, form)
 
 	case *sexp.Let:
 		compileLetStmt(cl, form)
@@ -86,6 +88,8 @@ func compileExpr(cl *Compiler, form sexp.Form) {
 		compileLambdaCall(cl, form)
 	case *sexp.DynCall:
 		compileDynCall(cl, form)
+	case *sexp.Lambda:
+		compileLambda(cl, form)
 	case *lapc.InstrCall:
 		compileInstrCall(cl, form)
 
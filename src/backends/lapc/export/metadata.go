@@ -13,6 +13,9 @@ func argsDescriptor(fn *sexp.Func) int {
 		panic(exn.User("can not have more than 127 positional parameters"))
 	}
 
+	if fn.Variadic {
+		arity-- // "&rest" arg is not positional
+	}
 	positionalArgs := uint32(arity) // First 7 bits: required args
 	const variadicBit = 128         // 8-th bit: "rest" arg
 	totalArgs := uint32(arity << 8) // Other bits
@@ -2,6 +2,7 @@ package export
 
 import (
 	"bytes"
+	"magic_pkg/emacs/lisp"
 	"strconv"
 )
 
@@ -44,7 +45,7 @@ func (w *writer) Write(val []byte) {
 }
 
 func (w *writer) WriteSymbol(val string) {
-	w.buf.WriteString(val)
+	w.buf.WriteString(lisp.Symbol(val).Repr())
 	w.buf.WriteByte(' ')
 }
 
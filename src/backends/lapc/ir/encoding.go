@@ -47,6 +47,7 @@ var kindToEncoding = [...]Encoding{
 	XlambdaRet:      Encoding{Name: []byte("%xlambda-ret")},
 	XlambdaEnter:    Encoding{Name: []byte("%xlambda-enter")},
 	XlambdaRetLabel: Encoding{Name: []byte("%xlambda-ret-label")},
+	XhandlerLabel:   Encoding{Name: []byte("%xhandler-label")},
 
 	Label:            Encoding{Name: []byte("label")},
 	Jmp:              Encoding{Name: []byte("goto")},
@@ -55,6 +56,9 @@ var kindToEncoding = [...]Encoding{
 	JmpNilElsePop:    jump("goto-if-nil-else-pop"),
 	JmpNotNilElsePop: jump("goto-if-not-nil-else-pop"),
 
+	PushConditionCase: jump("push-condition-case"),
+	PopHandler:        Encoding{Name: []byte("pop-handler")},
+
 	Return: returnEnc,
 	Call:   callEnc,
 
@@ -74,6 +78,7 @@ var kindToEncoding = [...]Encoding{
 	Sub1:   op1("sub1"),
 	Mul:    op2("mul"),
 	Quo:    op2("quo"),
+	Rem:    op2("rem"),
 	Min:    op2("min"),
 	Neg:    op1("neg"),
 
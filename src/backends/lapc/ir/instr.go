@@ -18,6 +18,7 @@ const (
 	XlambdaEnter
 	XlambdaRetLabel
 	XlambdaRet
+	XhandlerLabel
 
 	/* Emacs VM instructions */
 
@@ -28,6 +29,9 @@ const (
 	JmpNilElsePop    // "gotoifnilelsepop"
 	JmpNotNilElsePop // "gotoifnonnilelsepop"
 
+	PushConditionCase // "pushconditioncase"
+	PopHandler        // "pophandler"
+
 	Return
 	Call
 
@@ -45,6 +49,7 @@ const (
 	Sub    // "diff"
 	Mul    // "mult"
 	Quo
+	Rem
 	Add1
 	Sub1
 	Min
@@ -38,6 +38,13 @@ func (u *Unit) NewLambdaRetLabel() Instr {
 	return Instr{Kind: XlambdaRetLabel, Data: u.lastLabelID, Meta: "lambda-ret"}
 }
 
+// NewHandlerLabel returns a label that is used as
+// a condition-case handler entry point.
+func (u *Unit) NewHandlerLabel() Instr {
+	u.lastLabelID++
+	return Instr{Kind: XhandlerLabel, Data: u.lastLabelID, Meta: "handler"}
+}
+
 func (u *Unit) NewLabel(name string) Instr {
 	u.lastLabelID++
 	return Instr{Kind: Label, Data: u.lastLabelID, Meta: name}
@@ -95,6 +102,9 @@ func (p *InstrPusher) JmpNotNil(label Instr)        { p.pushLabel(JmpNotNil, lab
 func (p *InstrPusher) JmpNilElsePop(label Instr)    { p.pushLabel(JmpNilElsePop, label) }
 func (p *InstrPusher) JmpNotNilElsePop(label Instr) { p.pushLabel(JmpNotNilElsePop, label) }
 
+func (p *InstrPusher) PushConditionCase(label Instr) { p.pushLabel(PushConditionCase, label) }
+func (p *InstrPusher) PopHandler()                   { p.push(PopHandler) }
+
 func (p *InstrPusher) Return() { p.push(Return) }
 func (p *InstrPusher) Call(argc int, name string) {
 	p.PushInstr(Instr{Kind: Call, Data: int32(argc), Meta: name})
@@ -114,6 +124,7 @@ func (p *InstrPusher) Add()    { p.push(Add) }
 func (p *InstrPusher) Sub()    { p.push(Sub) }
 func (p *InstrPusher) Mul()    { p.push(Mul) }
 func (p *InstrPusher) Quo()    { p.push(Quo) }
+func (p *InstrPusher) Rem()    { p.push(Rem) }
 func (p *InstrPusher) Add1()   { p.push(Add1) }
 func (p *InstrPusher) Sub1()   { p.push(Sub1) }
 func (p *InstrPusher) Min()    { p.push(Min) }
@@ -18,6 +18,7 @@ func init() {
 		lisp.FnCons:     ir.Cons,
 		lisp.FnCar:      ir.Car,
 		lisp.FnCdr:      ir.Cdr,
+		lisp.FnSetcar:   ir.SetCar,
 		lisp.FnAref:     ir.Aref,
 		lisp.FnAset:     ir.Aset,
 		lisp.FnNumEq:    ir.NumEq,
@@ -31,6 +32,7 @@ func init() {
 		lisp.FnSub1:     ir.Sub1,
 		lisp.FnMul:      ir.Mul,
 		lisp.FnQuo:      ir.Quo,
+		lisp.FnRem:      ir.Rem,
 		lisp.FnMin:      ir.Min,
 		lisp.FnStrEq:    ir.StrEq,
 		lisp.FnStrLt:    ir.StrLt,
@@ -3,7 +3,9 @@ package dt
 import (
 	"bytes"
 	"magic_pkg/emacs/lisp"
+	"math"
 	"strconv"
+	"strings"
 )
 
 // ConstPool is a set of distincs constant values.
@@ -110,12 +112,30 @@ func (cp *ConstPool) Bytes() []byte {
 		case int64:
 			buf.WriteString(strconv.FormatInt(x, 10))
 		case float64:
-			buf.WriteString(strconv.FormatFloat(x, 'f', -1, 64))
+			buf.WriteString(formatFloat(x))
 		case lisp.Symbol:
-			buf.WriteString(string(x))
+			buf.WriteString(x.Repr())
 		}
 		buf.WriteByte(' ')
 	}
 	buf.WriteByte(']')
 	return buf.Bytes()
 }
+
+// formatFloat returns float representation that Emacs Lisp
+// reader does not confuse with integer.
+func formatFloat(x float64) string {
+	switch {
+	case math.IsInf(x, 1):
+		return "1.0e+INF"
+	case math.IsInf(x, -1):
+		return "-1.0e+INF"
+	case math.IsNaN(x):
+		return "0.0e+NaN"
+	}
+	s := strconv.FormatFloat(x, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
@@ -1,5 +1,9 @@
 package conformance
 
+import (
+	"emacs/lisp"
+)
+
 func sumArray1() int {
 	sum, xs := 0, [...]int{1, 2, 3}
 	for i := range xs {
@@ -7,3 +11,194 @@ func sumArray1() int {
 	}
 	return sum
 }
+
+func sumArray2() int {
+	sum, xs := 0, [...]int{1, 2, 3}
+	for _, x := range xs {
+		sum += x
+	}
+	return sum
+}
+
+func sumArrayKeyVal() int {
+	sum, xs := 0, [...]int{1, 2, 3}
+	for i, x := range xs {
+		sum += i * x
+	}
+	return sum
+}
+
+func rangeArrayAssign() int {
+	i, x := 0, 0
+	for i, x = range [...]int{5, 6, 7} {
+	}
+	return i*10 + x
+}
+
+func rangeKeyMutation() int {
+	n := 0
+	for i := range [...]int{1, 2, 3} {
+		i += 10
+		n++
+	}
+	return n
+}
+
+// Array is ranged over its copy, stores are not observed.
+func testRangeArrayCopy() int {
+	xs := [3]int{1, 2, 3}
+	sum := 0
+	for i, x := range xs {
+		if i == 0 {
+			xs[1], xs[2] = 10, 20
+		}
+		sum += x
+	}
+	return sum*100 + xs[1] + xs[2]
+}
+
+func sumSlice(xs []int) int {
+	sum := 0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum
+}
+
+func testRangeSlice(n int) int {
+	xs := make([]int, n)
+	for i := range xs {
+		xs[i] = 1
+	}
+	return sumSlice(xs)
+}
+
+func testRangeSliceKeyVal() int {
+	sum := 0
+	for i, x := range []int{10, 20, 30} {
+		if i == 1 {
+			continue
+		}
+		sum += x
+	}
+	return sum
+}
+
+func testRangeSliceBreak() int {
+	sum := 0
+	for _, x := range []int{1, 2, 3, 4} {
+		if x == 3 {
+			break
+		}
+		sum += x
+	}
+	return sum
+}
+
+func testRangeNested() int {
+	sum := 0
+	for _, x := range []int{1, 2} {
+		for _, y := range []int{10, 20} {
+			sum += x * y
+		}
+	}
+	return sum
+}
+
+func testRangeString(s string) string {
+	res := ""
+	for i, ch := range s {
+		res += lisp.Call("format", "%d:%c;", i, ch).String()
+	}
+	return res
+}
+
+func testRangeStringKeys(s string) int {
+	last := -1
+	for i := range s {
+		last = i
+	}
+	return last
+}
+
+func testRangeMap(n int) int {
+	m := make(map[int]int)
+	for i := 1; i <= n; i++ {
+		m[i] = i * 2
+	}
+	sumKeys, sumVals := 0, 0
+	for k, v := range m {
+		sumKeys += k
+		sumVals += v
+	}
+	return sumVals - sumKeys
+}
+
+func testRangeMapKeys() int {
+	m := make(map[string]int)
+	m["a"] = 1
+	m["b"] = 2
+	count := 0
+	for range m {
+		count++
+	}
+	return count
+}
+
+func testRangeMapDelete(n int) int {
+	m := make(map[int]int)
+	for i := 1; i <= n; i++ {
+		m[i] = i
+	}
+	count := 0
+	for k := range m {
+		count++
+		for i := 1; i <= n; i++ {
+			if i != k {
+				delete(m, i)
+			}
+		}
+	}
+	return count
+}
+
+func testRangeMapUpdate(n int) int {
+	m := make(map[int]int)
+	for i := 1; i <= n; i++ {
+		m[i] = 1
+	}
+	sum, first := 0, true
+	for k, v := range m {
+		sum += v
+		if first {
+			first = false
+			for i := 1; i <= n; i++ {
+				if i != k {
+					m[i] = 10
+				}
+			}
+		}
+	}
+	return sum
+}
+
+func testRangeLispList() int {
+	sum := 0
+	for i, x := range lisp.Seq(lisp.Call("list", 1, 2, 3)) {
+		sum += i * x.Int()
+	}
+	return sum
+}
+
+func testRangeLispVector() int {
+	sum := 0
+	for _, x := range lisp.Seq(lisp.Call("vector", 1, 2, 3)) {
+		sum += x.Int()
+	}
+	return sum
+}
+
+func testLispSeqSlice() int {
+	xs := lisp.Seq(lisp.Call("list", 1, 2, 3))
+	return len(xs) + xs[2].Int()
+}
@@ -56,3 +56,78 @@ func testMapLen(n int) int {
 	}
 	return n
 }
+
+type mapEntry struct {
+	name string
+	size int
+}
+
+var mapConfig = map[string]mapEntry{
+	"a": {name: "alpha", size: 1},
+	"b": {"beta", 2},
+}
+
+func testMapLit() int {
+	xs := map[string]int{"a": 1, "b": 2, "c": 3}
+	return len(xs)*100 + xs["a"] + xs["c"]*10
+}
+
+func testMapLitEmpty() int {
+	xs := map[int]int{}
+	xs[1] = 5
+	return len(xs) + xs[1]
+}
+
+func testMapLitNested() int {
+	xs := map[string]map[string]int{
+		"x": {"a": 1},
+		"y": {"a": 2, "b": 3},
+	}
+	return len(xs["y"])*10 + xs["y"]["b"]
+}
+
+func testMapLitStruct() string {
+	return mapConfig["a"].name + mapConfig["b"].name
+}
+
+func testMapLitPtr() int {
+	xs := map[int]*mapEntry{1: {size: 7}}
+	xs[1].size++
+	return xs[1].size
+}
+
+func testMapCommaOk() string {
+	xs := map[string]int{"zero": 0}
+	res := ""
+	if v, ok := xs["zero"]; ok && v == 0 {
+		res += "a"
+	}
+	if v, ok := xs["none"]; !ok && v == 0 {
+		res += "b"
+	}
+	var ok bool
+	_, ok = xs["zero"]
+	if ok {
+		res += "c"
+	}
+	return res
+}
+
+func testMapCommaOkNil() string {
+	var xs map[string]int
+	v, ok := xs["a"]
+	if !ok && v == 0 && len(xs) == 0 && xs == nil {
+		return "ok"
+	}
+	return "fail"
+}
+
+func testMapNilDelete() int {
+	var xs map[string]int
+	delete(xs, "a")
+	n := 0
+	for range xs {
+		n++
+	}
+	return n
+}
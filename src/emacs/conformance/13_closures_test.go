@@ -0,0 +1,108 @@
+package conformance
+
+import (
+	"emacs/lisp"
+)
+
+var globalInc = func(x int) int { return x + 1 }
+
+func applyIntFn(f func(int) int, x int) int {
+	return f(x)
+}
+
+func makeCounter() func() int {
+	n := 0
+	return func() int {
+		n++
+		return n
+	}
+}
+
+func makeAdder(delta int) func(int) int {
+	return func(x int) int { return x + delta }
+}
+
+func double(x int) int { return x * 2 }
+
+func testClosureCall(n int) int {
+	f := func() int { return n }
+	return f()
+}
+
+func testClosureCounter(n int) int {
+	next := makeCounter()
+	for i := 0; i < n-1; i++ {
+		next()
+	}
+	return next()
+}
+
+func testClosureShared(n int) int {
+	x := 0
+	set := func(v int) { x = v }
+	get := func() int { return x }
+	set(n)
+	return get()
+}
+
+func testClosureParamMutation(n int) int {
+	add := func(delta int) { n += delta }
+	add(1)
+	add(-1)
+	return n
+}
+
+func testClosureNested(n int) int {
+	outer := func() func() int {
+		return func() int { return n }
+	}
+	return outer()()
+}
+
+func testClosureAdder(n int) int {
+	return applyIntFn(makeAdder(n), 0)
+}
+
+func testFuncValue(n int) int {
+	f := double
+	return applyIntFn(f, n) / 2
+}
+
+func testClosureIIFE(n int) int {
+	return func(x int) int { return x }(n)
+}
+
+func testClosureMultiResult(n int) int {
+	split := func() (int, int) { return n, n }
+	a, b := split()
+	return (a + b) / 2
+}
+
+func testClosureGlobal(n int) int {
+	return globalInc(n - 1)
+}
+
+func testClosureMapcar(n int) int {
+	xs := lisp.Call("list", n)
+	ys := lisp.Call("mapcar", func(x lisp.Object) int {
+		return x.Int() + 1
+	}, xs)
+	return lisp.Call("car", ys).Int() - 1
+}
+
+func testClosureIgnoredCall() int {
+	n := 0
+	inc := func() int {
+		n++
+		return n
+	}
+	_ = inc()
+	_, _ = inc(), double(1)
+	_ = func() int {
+		n += 10
+		return n
+	}()
+	_ = lisp.Call("funcall", inc)
+	_ = []int{inc()}
+	return n
+}
@@ -0,0 +1,131 @@
+package conformance
+
+import (
+	"emacs/lisp"
+)
+
+var deferLog string
+
+func deferAppend(s string) {
+	deferLog += s
+}
+
+func deferOrder() {
+	defer deferAppend("a")
+	defer deferAppend("b")
+	defer deferAppend("c")
+}
+
+func deferArgs() {
+	s := "x"
+	defer deferAppend(s)
+	s = "y"
+	deferAppend(s)
+}
+
+func deferEarlyReturn(n int) int {
+	defer deferAppend("d")
+	for i := 0; i < n; i++ {
+		if i == 5 {
+			return i
+		}
+	}
+	return n
+}
+
+func deferPanic() {
+	defer deferAppend("p")
+	panic("boom")
+}
+
+var recovered lisp.Object
+
+func recoverValue() {
+	defer func() {
+		recovered = lisp.Call("identity", recover())
+	}()
+	panic("boom")
+}
+
+func recoverZero(n int) int {
+	defer func() { recover() }()
+	if n > 0 {
+		panic(n)
+	}
+	return n
+}
+
+func recoverNested() string {
+	defer func() { recover() }()
+	deferPanic()
+	return "unreachable"
+}
+
+func recoverReplaced() {
+	defer func() {
+		recovered = lisp.Call("identity", recover())
+	}()
+	defer deferAppend("a")
+	defer func() { panic("second") }()
+	defer deferAppend("b")
+	panic("first")
+}
+
+func recoverNoPanic() bool {
+	r := lisp.Call("identity", recover())
+	return lisp.Call("null", r).Bool()
+}
+
+func testDeferOrder() string {
+	deferLog = ""
+	deferOrder()
+	return deferLog
+}
+
+func testDeferArgs() string {
+	deferLog = ""
+	deferArgs()
+	return deferLog
+}
+
+func testDeferEarlyReturn(n int) int {
+	deferLog = ""
+	res := deferEarlyReturn(n)
+	if deferLog == "d" {
+		return res
+	}
+	return -1
+}
+
+func testDeferClosure(n int) int {
+	x := 0
+	func() {
+		defer func() { x = n }()
+	}()
+	return x
+}
+
+func testRecoverValue() string {
+	recoverValue()
+	return recovered.String()
+}
+
+func testRecoverZero(n int) int {
+	return recoverZero(n)
+}
+
+func testRecoverNested() string {
+	deferLog = ""
+	recoverNested()
+	return deferLog
+}
+
+func testRecoverReplaced() string {
+	deferLog = ""
+	recoverReplaced()
+	return deferLog + recovered.String()
+}
+
+func testRecoverNoPanic() bool {
+	return recoverNoPanic()
+}
@@ -0,0 +1,190 @@
+package conformance
+
+import (
+	"emacs/lisp"
+)
+
+type shape interface {
+	area() int
+}
+
+type square struct{ side int }
+type rect struct{ w, h int }
+type circle struct{ r int }
+
+func (s *square) area() int { return s.side * s.side }
+func (r *rect) area() int   { return r.w * r.h }
+func (c *circle) area() int { return 3 * c.r * c.r }
+
+func shapeKind(s shape) string {
+	switch s.(type) {
+	case *square:
+		return "square"
+	case *rect:
+		return "rect"
+	case nil:
+		return "nil"
+	default:
+		return "other"
+	}
+}
+
+func shapeSide(s shape) int {
+	switch s := s.(type) {
+	case *square:
+		return s.side
+	case *rect, *circle:
+		return s.area()
+	}
+	return -1
+}
+
+func objectKind(x interface{}) string {
+	switch x := x.(type) {
+	case int:
+		return "int" + lisp.Call("number-to-string", x).String()
+	case float64:
+		return "float"
+	case string:
+		return "string:" + x
+	case lisp.Symbol:
+		return "symbol"
+	default:
+		return "object"
+	}
+}
+
+func objectIsSymbol(x lisp.Object) bool {
+	switch x.(type) {
+	case lisp.Symbol:
+		return true
+	}
+	return false
+}
+
+func testTypeSwitchIface() string {
+	var s shape
+	return shapeKind(&square{side: 1}) +
+		shapeKind(&rect{w: 1, h: 2}) +
+		shapeKind(&circle{r: 1}) +
+		shapeKind(s)
+}
+
+func testTypeSwitchBind(n int) int {
+	return shapeSide(&square{side: n})
+}
+
+func testTypeSwitchMulti(n int) int {
+	return shapeSide(&rect{w: n, h: 1}) + shapeSide(&circle{r: 1}) - 3
+}
+
+func testTypeSwitchLispInt(n int) string {
+	return objectKind(lisp.Call("identity", n))
+}
+
+func testTypeSwitchLispString(s string) string {
+	return objectKind(lisp.Call("identity", s))
+}
+
+func testTypeSwitchLispOther() string {
+	return objectKind(lisp.Call("intern", "foo")) +
+		objectKind(lisp.Call("float", 1)) +
+		objectKind(lisp.Call("list", 1))
+}
+
+func testTypeSwitchLispSymbol() bool {
+	return objectIsSymbol(lisp.Call("intern", "foo")) &&
+		!objectIsSymbol(lisp.Call("list", 1))
+}
+
+type badge struct{ text string }
+
+func (b badge) area() int { return len(b.text) }
+
+func badgeKind(s shape) string {
+	switch s.(type) {
+	case badge:
+		return "value"
+	case *badge:
+		return "ptr"
+	}
+	return "other"
+}
+
+func valueKind(x interface{}) string {
+	switch x := x.(type) {
+	case int:
+		return "int"
+	case badge:
+		return "badge:" + x.text
+	case *badge:
+		return "*badge:" + x.text
+	case *square:
+		return "square"
+	case shape:
+		return "shape"
+	}
+	return "other"
+}
+
+func testTypeSwitchPtrTag() string {
+	return badgeKind(badge{text: "a"}) + badgeKind(&badge{text: "b"})
+}
+
+func testTypeSwitchEmptyIface() string {
+	return valueKind(1) + "," +
+		valueKind(badge{text: "a"}) + "," +
+		valueKind(&badge{text: "b"}) + "," +
+		valueKind(&square{side: 1}) + "," +
+		valueKind(&rect{}) + "," +
+		valueKind("s")
+}
+
+func testTypeAssertEmptyIface(n int) int {
+	var x interface{} = badge{text: "abc"}
+	if _, ok := x.(*badge); ok {
+		return -1
+	}
+	return x.(badge).area() * n
+}
+
+type badges []badge
+
+func testTypeSwitchNamedSlice() int {
+	var x interface{} = badges{{text: "a"}, {text: "b"}}
+	switch x := x.(type) {
+	case badge:
+		return -1
+	case badges:
+		return len(x)
+	}
+	return 0
+}
+
+func nilKind(x interface{}) string {
+	switch x.(type) {
+	case bool:
+		return "bool"
+	case lisp.Symbol:
+		return "symbol"
+	case interface{}:
+		return "any"
+	}
+	return "default"
+}
+
+func nilObjectKind(x lisp.Object) string {
+	switch x.(type) {
+	case lisp.Symbol:
+		return "symbol"
+	}
+	return "default"
+}
+
+// Nil interface matches only "case nil" and "default".
+func testTypeSwitchNilIface() string {
+	var x interface{}
+	var o lisp.Object
+	return nilKind(x) + "," + nilObjectKind(o) + "," +
+		nilKind(true) + "," + nilKind(lisp.Intern("a")) + "," + nilKind(1)
+}
@@ -0,0 +1,98 @@
+package conformance
+
+import (
+	"emacs/lisp"
+)
+
+type namer interface {
+	name() string
+}
+
+type sizer interface {
+	size() int
+}
+
+type file struct{ n int }
+type dir struct{ n int }
+
+func (f *file) name() string { return "file" }
+func (f *file) size() int    { return f.n }
+func (d *dir) name() string  { return "dir" }
+
+// entrySize probes optional sizer capability.
+func entrySize(x namer) int {
+	if s, ok := x.(sizer); ok {
+		return s.size()
+	}
+	return -1
+}
+
+func testTypeAssertOk(n int) int {
+	var x namer = &file{n: n}
+	f, ok := x.(*file)
+	if !ok {
+		return -1
+	}
+	return f.n
+}
+
+func testTypeAssertOkFail() bool {
+	var x namer = &dir{}
+	_, ok := x.(*file)
+	return !ok
+}
+
+func testTypeAssertOkNil() bool {
+	var x namer
+	_, ok := x.(*file)
+	return !ok
+}
+
+func testTypeAssert(n int) int {
+	var x namer = &file{n: n}
+	return x.(*file).n
+}
+
+func testTypeAssertIface(n int) int {
+	return entrySize(&file{n: n}) + entrySize(&dir{}) + 1
+}
+
+func testTypeAssertIfaceMethod() string {
+	var x sizer = &file{}
+	return x.(namer).name()
+}
+
+func testIfaceConv(n int) int {
+	var s sizer = &file{n: n}
+	var x namer = s.(namer)
+	return x.(sizer).size()
+}
+
+func testTypeAssertLispOk(n int) int {
+	var x interface{} = lisp.Call("identity", n)
+	if _, ok := x.(string); ok {
+		return -1
+	}
+	v, ok := x.(int)
+	if !ok {
+		return -1
+	}
+	return v
+}
+
+func testTypeAssertLispOkFail() bool {
+	var x interface{} = lisp.Call("identity", "s")
+	v, ok := x.(int)
+	return !ok && v == 0
+}
+
+// link has "size" method with a different signature,
+// so it does not implement sizer.
+type link struct{ target string }
+
+func (l *link) name() string { return "link" }
+func (l *link) size() string { return l.target }
+
+func testTypeAssertSignature(n int) int {
+	return entrySize(&link{target: "x"}) + entrySize(&file{n: n})
+}
@@ -0,0 +1,124 @@
+package conformance
+
+import (
+	"emacs/lisp"
+	"emacs/time"
+)
+
+func chanSendRange(ch chan int, n int) {
+	for i := 1; i <= n; i++ {
+		ch <- i
+	}
+	close(ch)
+}
+
+func chanSum(ch <-chan int) int {
+	sum := 0
+	for x := range ch {
+		sum += x
+	}
+	return sum
+}
+
+func testChanBuffered(n int) int {
+	ch := make(chan int, 2)
+	ch <- n
+	ch <- 1
+	x := <-ch
+	return x + <-ch - 1
+}
+
+func testChanUnbuffered(n int) int {
+	ch := make(chan int)
+	go func() { ch <- n }()
+	return <-ch
+}
+
+func testChanRange(n int) int {
+	ch := make(chan int)
+	go chanSendRange(ch, n)
+	return chanSum(ch)
+}
+
+func testChanRangeBuffered(n int) int {
+	ch := make(chan int, n)
+	chanSendRange(ch, n)
+	return chanSum(ch)
+}
+
+func testChanPingPong(n int) int {
+	ping := make(chan int)
+	pong := make(chan int)
+	go func() {
+		for x := range ping {
+			pong <- x + 1
+		}
+		close(pong)
+	}()
+	x := 0
+	for i := 0; i < n; i++ {
+		ping <- x
+		x = <-pong
+	}
+	close(ping)
+	_, ok := <-pong
+	if ok {
+		return -1
+	}
+	return x
+}
+
+func testChanClosedRecv() bool {
+	ch := make(chan string, 1)
+	ch <- "x"
+	close(ch)
+	a, ok1 := <-ch
+	b, ok2 := <-ch
+	return a == "x" && ok1 && b == "" && !ok2
+}
+
+func testChanLenCap() int {
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	<-ch
+	return len(ch)*10 + cap(ch)
+}
+
+func testChanUnbufferedLenCap() int {
+	var ch chan int
+	return len(ch) + cap(ch) + cap(make(chan int))
+}
+
+func chanCloseTwice() {
+	defer func() {
+		recovered = lisp.Call("identity", recover())
+	}()
+	ch := make(chan int)
+	close(ch)
+	close(ch)
+}
+
+func testChanCloseTwice() string {
+	chanCloseTwice()
+	return recovered.String()
+}
+
+// Blocked unbuffered send panics when channel is closed;
+// its value is not received.
+func testChanSendClosed(n int) string {
+	ch := make(chan int)
+	done := make(chan string, 1)
+	go func() {
+		defer func() {
+			done <- recover().(string)
+		}()
+		ch <- n
+	}()
+	time.Sleep(time.Millisecond)
+	close(ch)
+	if _, ok := <-ch; ok {
+		return "received"
+	}
+	return <-done
+}
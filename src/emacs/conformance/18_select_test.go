@@ -0,0 +1,192 @@
+package conformance
+
+import (
+	"emacs/lisp"
+	"emacs/time"
+)
+
+func testSelectDefault() string {
+	ch := make(chan int)
+	select {
+	case <-ch:
+		return "recv"
+	default:
+		return "default"
+	}
+}
+
+func testSelectRecv(n int) int {
+	a := make(chan int, 1)
+	b := make(chan int, 1)
+	b <- n
+	select {
+	case x := <-a:
+		return x
+	case x := <-b:
+		return x
+	}
+}
+
+func testSelectSend(n int) int {
+	a := make(chan int)
+	b := make(chan int, 1)
+	select {
+	case a <- 1:
+		return -1
+	case b <- n:
+		return <-b
+	}
+}
+
+func testSelectBlocking(n int) int {
+	ch := make(chan int)
+	go func() { ch <- n }()
+	select {
+	case x := <-ch:
+		return x
+	}
+}
+
+func testSelectSendToRecv(n int) int {
+	ch := make(chan int)
+	done := make(chan bool, 1)
+	go func() {
+		select {
+		case ch <- n:
+		}
+		done <- true
+	}()
+	x := <-ch
+	<-done
+	return x
+}
+
+func testSelectSendToSelect(n int) int {
+	ch := make(chan int)
+	go func() {
+		select {
+		case ch <- n:
+		}
+	}()
+	select {
+	case x := <-ch:
+		return x
+	}
+}
+
+func testSelectClosed() bool {
+	ch := make(chan int)
+	close(ch)
+	select {
+	case x, ok := <-ch:
+		return x == 0 && !ok
+	default:
+		return false
+	}
+}
+
+func testSelectNilChan(n int) int {
+	var nilCh chan int
+	ch := make(chan int, 1)
+	ch <- n
+	res := 0
+	for i := 0; i < 10; i++ {
+		select {
+		case nilCh <- 1:
+			return -1
+		case <-nilCh:
+			return -1
+		case x := <-ch:
+			res = x
+			ch <- x
+		}
+	}
+	return res
+}
+
+func testSelectFairness() bool {
+	a := make(chan int, 100)
+	b := make(chan int, 100)
+	for i := 0; i < 100; i++ {
+		a <- i
+		b <- i
+	}
+	countA := 0
+	countB := 0
+	for i := 0; i < 100; i++ {
+		select {
+		case <-a:
+			countA++
+		case <-b:
+			countB++
+		}
+	}
+	return countA > 0 && countB > 0
+}
+
+func testSelectTimeout() string {
+	timeout := time.After(time.Millisecond)
+	never := make(chan int)
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-never:
+		return "recv"
+	case <-timeout:
+		return "timeout"
+	default:
+		return "default"
+	}
+}
+
+func selectSendClosed() {
+	defer func() {
+		recovered = lisp.Call("identity", recover())
+	}()
+	ch := make(chan int)
+	close(ch)
+	select {
+	case ch <- 1:
+	}
+}
+
+// Panic inside "select" must not leave it locked
+// for other threads. This thread polls channel
+// length, so it does not enter "select" again.
+func testSelectSendClosed(n int) string {
+	selectSendClosed()
+	ch := make(chan int, 1)
+	go func() {
+		select {
+		case ch <- n:
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if len(ch) == 1 {
+			return recovered.String()
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return "fail"
+}
+
+func selectChan(log *string, s string, ch chan int) chan int {
+	*log += s
+	return ch
+}
+
+func selectValue(log *string, s string, x int) int {
+	*log += s
+	return x
+}
+
+func testSelectEvalOrder() string {
+	log := ""
+	a := make(chan int)
+	b := make(chan int, 1)
+	select {
+	case selectChan(&log, "a", a) <- selectValue(&log, "1", 1):
+	case <-selectChan(&log, "b", a):
+	case selectChan(&log, "c", b) <- selectValue(&log, "2", 2):
+	}
+	return log
+}
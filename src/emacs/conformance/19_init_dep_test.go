@@ -0,0 +1,21 @@
+package conformance
+
+import (
+	"emacs/conformance/initdep"
+)
+
+// This file precedes "19_init_test.go" in file order,
+// but its variable depends on initVar declared there.
+var initDepVar = initAppend("w" + initVar)
+
+// Imported package is initialized before
+// variables of importing package.
+var initDepCount = initdep.InitCount
+
+func init() {
+	initAppend("0")
+}
+
+func testInitImportedOnce() int {
+	return initDepCount*10 + initdep.InitCount
+}
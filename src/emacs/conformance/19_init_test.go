@@ -0,0 +1,23 @@
+package conformance
+
+var initLog string
+
+// Variable initializers are evaluated before "init" functions.
+var initVar = initAppend("v")
+
+func initAppend(s string) string {
+	initLog += s
+	return s
+}
+
+func init() {
+	initAppend("1")
+}
+
+func init() {
+	initAppend("2")
+}
+
+func testInitOrder() string {
+	return initLog
+}
@@ -10,6 +10,37 @@ func gtInt(x, y int) bool    { return x > y }
 func ltInt(x, y int) bool    { return x < y }
 func incInt(x int) int       { x++; return x }
 func decInt(x int) int       { x--; return x }
+func remInt(x, y int) int    { return x % y }
+func andInt(x, y int) int    { return x & y }
+func orInt(x, y int) int     { return x | y }
+func xorInt(x, y int) int    { return x ^ y }
+func andNotInt(x, y int) int { return x &^ y }
+func notInt(x int) int       { return ^x }
+func shlInt(x, y int) int    { return x << uint(y) }
+func shrInt(x, y int) int    { return x >> uint(y) }
+func notUint8(x uint8) uint8 { return ^x }
+
+func notUint(x uint) uint       { return ^x }
+func notUint64(x uint64) uint64 { return ^x }
+
+func opAssignInt(x int) int {
+	x -= 1
+	x *= 10
+	x /= 3
+	x %= 7
+	x |= 8
+	x &= 12
+	x ^= 5
+	x <<= 2
+	x >>= 1
+	x &^= 2
+	return x
+}
+
+func quoZero(x, y int) int {
+	defer func() { recover() }()
+	return x/y + 1
+}
 
 func add1Float(x float64) float64      { return x + 1 }
 func addFloat(x, y, z float64) float64 { return x + y + z }
@@ -25,3 +56,13 @@ func decFloat(x float64) float64       { x--; return x }
 func concatStr(x, y, z string) string { return x + y + z }
 func ltStr(x, y string) bool          { return x < y }
 func eqStr(x, y string) bool          { return x == y }
+
+func testQuoZeroValue(x, y int) (res string) {
+	defer func() {
+		if r := recover(); r != nil {
+			res = r.(string)
+		}
+	}()
+	_ = x % y
+	return "no panic"
+}
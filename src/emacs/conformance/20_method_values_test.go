@@ -0,0 +1,60 @@
+package conformance
+
+import (
+	"emacs/lisp"
+)
+
+type counter struct{ n int }
+
+func (c *counter) add(x int) int {
+	c.n = c.n + x
+	return c.n
+}
+
+func (c counter) get() int { return c.n }
+
+func testMethodValue(n int) int {
+	c := &counter{}
+	add := c.add
+	add(n)
+	add(n)
+	return c.n
+}
+
+func testMethodValueCopy(n int) int {
+	c := counter{n: n}
+	get := c.get
+	c.n = 0
+	return get()
+}
+
+func testMethodValueAddr(n int) int {
+	var c counter
+	add := c.add
+	add(n)
+	return c.n
+}
+
+func testMethodExpr(n int) int {
+	add := (*counter).add
+	c := &counter{}
+	add(c, n)
+	return add(c, n)
+}
+
+func testMethodExprValue(n int) int {
+	get := counter.get
+	return get(counter{n: n})
+}
+
+func testMethodValueIface(n int) int {
+	var s shape = &square{side: n}
+	area := s.area
+	return area()
+}
+
+func testMethodValueFuncall(n int) int {
+	c := &counter{}
+	lisp.Call("funcall", c.add, n)
+	return c.n
+}
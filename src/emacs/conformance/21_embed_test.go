@@ -0,0 +1,95 @@
+package conformance
+
+import (
+	"emacs/lisp"
+)
+
+type embedBase struct {
+	id   int
+	name string
+}
+
+func (b *embedBase) setID(id int) { b.id = id }
+func (b embedBase) getID() int    { return b.id }
+func (b *embedBase) area() int    { return b.id * b.id }
+
+type embedMiddle struct {
+	embedBase
+	level int
+}
+
+type embedOuter struct {
+	tag string
+	embedMiddle
+}
+
+type embedPtr struct {
+	*embedBase
+	x int
+}
+
+type embedIface struct {
+	shape
+	scale int
+}
+
+func testEmbedField(n int) int {
+	m := embedMiddle{embedBase: embedBase{id: n}, level: 1}
+	return m.id + m.embedBase.id + m.level
+}
+
+func testEmbedFieldDeep(n int) string {
+	var o embedOuter
+	o.id = n
+	o.name = "deep"
+	o.level = n
+	return o.embedMiddle.embedBase.name + lisp.Call("number-to-string", o.id+o.level).String()
+}
+
+func testEmbedMethod(n int) int {
+	var o embedOuter
+	o.setID(n)
+	return o.getID() + o.embedMiddle.getID()
+}
+
+func testEmbedPtrField(n int) int {
+	b := &embedBase{id: n}
+	p := embedPtr{embedBase: b}
+	p.id = p.id + 1
+	return b.id
+}
+
+func testEmbedPtrMethod(n int) int {
+	p := &embedPtr{embedBase: &embedBase{}}
+	p.setID(n)
+	return p.getID()
+}
+
+func testEmbedIfaceCall(n int) int {
+	var s shape = &embedOuter{embedMiddle: embedMiddle{embedBase: embedBase{id: n}}}
+	return s.area()
+}
+
+func testEmbedMethodValue(n int) int {
+	var m embedMiddle
+	set := m.setID
+	set(n)
+	return m.id
+}
+
+func testEmbedMethodExpr(n int) int {
+	var m embedMiddle
+	(*embedMiddle).setID(&m, n)
+	get := embedMiddle.getID
+	return get(m)
+}
+
+func testEmbedEmbeddedIface(n int) int {
+	e := embedIface{shape: &square{side: n}, scale: 2}
+	return e.area() * e.scale
+}
+
+func testEmbedEmbeddedIfaceItab(n int) int {
+	var s shape = &embedIface{shape: &square{side: n}, scale: 2}
+	return s.area()
+}
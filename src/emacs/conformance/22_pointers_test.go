@@ -0,0 +1,163 @@
+package conformance
+
+type ptrUnit struct{ v int }
+type ptrPair struct{ x, y int }
+type ptrTriple struct{ a, b, c int }
+type ptrBig struct{ a, b, c, d, e, f int }
+type ptrNested struct {
+	in  ptrPair
+	arr [2]ptrUnit
+}
+
+type ptrCounter int
+
+func (c *ptrCounter) inc() { *c = *c + 1 }
+
+var ptrGlobal int
+
+func ptrParse(s string, out *int) bool {
+	if s == "" {
+		return false
+	}
+	*out = len(s)
+	return true
+}
+
+func ptrSwap(a, b *string) {
+	*a, *b = *b, *a
+}
+
+func testPtrLocal(n int) int {
+	x := 0
+	p := &x
+	*p = n
+	return x + *p
+}
+
+func testPtrOutParam(s string) int {
+	var n int
+	if ptrParse(s, &n) {
+		return n
+	}
+	return -1
+}
+
+func testPtrSwap() string {
+	a, b := "a", "b"
+	ptrSwap(&a, &b)
+	return a + b
+}
+
+func testPtrArrayElem(n int) int {
+	arr := [3]int{1, 2, 3}
+	p := &arr[1]
+	*p = n
+	return arr[1] + *p
+}
+
+func testPtrSliceElem(n int) int {
+	s := []int{1, 2, 3}
+	p := &s[1:][1]
+	*p = n
+	return s[2]
+}
+
+func testPtrFields(n int) int {
+	var u ptrUnit
+	var pair ptrPair
+	var t ptrTriple
+	b := &ptrBig{}
+	*(&u.v) = n
+	*(&pair.x) = n
+	*(&pair.y) = n
+	pa, pb, pc := &t.a, &t.b, &t.c
+	*pa, *pb, *pc = n, n, n
+	pd := &b.d
+	*pd = n
+	return u.v + pair.x + pair.y + t.a + t.b + t.c + b.d
+}
+
+func testPtrGlobal(n int) int {
+	p := &ptrGlobal
+	*p = n
+	return ptrGlobal
+}
+
+func testPtrCaptured(n int) int {
+	x := 0
+	inc := func() { x = x + 1 }
+	p := &x
+	*p = n
+	inc()
+	return *p
+}
+
+func testPtrNew(n int) int {
+	p := new(int)
+	*p = n
+	q := new(ptrPair)
+	q.x = *p
+	return q.x + *p
+}
+
+func testPtrStructAssign(n int) int {
+	a := &ptrTriple{a: 1}
+	b := a
+	*a = ptrTriple{a: n, c: n}
+	return b.a + b.b + b.c
+}
+
+func testPtrArray(n int) int {
+	var arr [2]int
+	p := &arr
+	p[0] = n
+	(*p)[1] = n
+	return arr[0] + arr[1]
+}
+
+func testPtrVarAssign(n int) int {
+	var s ptrPair
+	p := &s
+	s = ptrPair{x: n}
+	return p.x
+}
+
+func testPtrNestedAssign(n int) int {
+	var o ptrNested
+	in, elem := &o.in, &o.arr[1]
+	o = ptrNested{in: ptrPair{x: n}, arr: [2]ptrUnit{{v: 1}, {v: n}}}
+	return in.x + elem.v
+}
+
+func testPtrArrayVarAssign(n int) int {
+	var arr [3]int
+	p := &arr
+	arr = [3]int{n, 0, n}
+	return p[0] + p[2]
+}
+
+func testPtrParallelAssign() int {
+	a, b := ptrPair{x: 1}, ptrPair{x: 2}
+	p := &a
+	a, b = b, a
+	return p.x*10 + b.x
+}
+
+func testPtrMethod() int {
+	var c ptrCounter
+	c.inc()
+	c.inc()
+	return int(c)
+}
+
+func testPtrNil() bool {
+	var p *int
+	return p == nil
+}
+
+func testPtrEq() bool {
+	x, y := 1, 1
+	p, q, r := &x, &x, &y
+	s := &ptrPair{}
+	return p == q && p != r && p != nil && s == s
+}
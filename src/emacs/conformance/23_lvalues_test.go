@@ -0,0 +1,119 @@
+package conformance
+
+import (
+	"emacs/lisp"
+)
+
+type lvCounter struct {
+	count int
+	name  string
+}
+
+type lvMap map[string]int
+
+func lvIndex(log *string, i int) int {
+	*log += "i"
+	return i
+}
+
+func testIncField(n int) int {
+	c := &lvCounter{}
+	for i := 0; i < n; i++ {
+		c.count++
+	}
+	c.count += n
+	c.name += "x"
+	return c.count + len(c.name)
+}
+
+func testIncArray(n int) int {
+	var arr [3]int
+	arr[1]++
+	arr[1] += n
+	arr[2]--
+	return arr[1] + arr[2]
+}
+
+func testIncSlice(n int) int {
+	s := make([]int, 2)
+	s[1]++
+	s[1] *= n
+	return s[1]
+}
+
+func testIncStructElem(n int) int {
+	s := make([]lvCounter, 2)
+	s[1].count += n
+	s[1].count++
+	return s[1].count
+}
+
+func testIncMap() int {
+	m := make(map[string]int)
+	m["a"]++
+	m["a"]++
+	m["b"] += 5
+	return m["a"]*10 + m["b"]
+}
+
+func testIncNamedMap() int {
+	m := make(lvMap)
+	m["k"]++
+	return m["k"]
+}
+
+func testIncPtr(n int) int {
+	x := n
+	p := &x
+	*p++
+	*p *= 2
+	return x
+}
+
+func testIncOnce() string {
+	log := ""
+	arr := []int{0, 0, 0}
+	arr[lvIndex(&log, 1)]++
+	arr[lvIndex(&log, 2)] += 3
+	return log + lisp.Call("number-to-string", arr[1]+arr[2]).String()
+}
+
+func testSwapVars() int {
+	x, y := 1, 2
+	x, y = y, x
+	return x*10 + y
+}
+
+func testParallelAssign() int {
+	a := []int{1, 2, 3}
+	a[0], a[2] = a[2], a[0]
+	i := 0
+	i, a[i] = 1, 9
+	return a[0]*100 + a[2]*10 + i
+}
+
+func testParallelAssignIface() string {
+	m := make(map[int]interface{})
+	xs := make([]shape, 1)
+	m[0], xs[0] = badge{"a"}, badge{"bc"}
+	res := ""
+	if b, ok := m[0].(badge); ok {
+		res += b.text
+	}
+	return res + badgeKind(xs[0])
+}
+
+// Structs of 3 and 4 fields are chained conses;
+// middle fields are updated with setcar.
+type lvTriple struct{ a, b, c int }
+
+type lvQuad struct{ a, b, c, d int }
+
+func testMiddleFieldStore(n int) int {
+	t := lvTriple{1, 2, 3}
+	t.b = n
+	q := &lvQuad{1, 2, 3, 4}
+	q.b += n
+	q.c = n * 2
+	return t.a*1000000 + t.b*10000 + t.c*100 + q.a + q.b + q.c + q.d
+}
@@ -0,0 +1,105 @@
+package conformance
+
+import (
+	"emacs/lisp"
+)
+
+func namedZero() (n int, s string) {
+	return
+}
+
+func namedNaked(x int) (n int, ok bool) {
+	if x < 0 {
+		return
+	}
+	n = x * 2
+	ok = true
+	return
+}
+
+func namedExplicit(x int) (n int) {
+	n = 1
+	return x
+}
+
+func namedSwap(a, b string) (x, y string) {
+	x, y = a, b
+	return y, x
+}
+
+func namedDeferModify(x int) (n int) {
+	defer func() { n *= 2 }()
+	return x
+}
+
+func namedRecover(x int) (n int, failed bool) {
+	defer func() {
+		r := lisp.Call("identity", recover())
+		failed = !lisp.Call("null", r).Bool()
+	}()
+	n = x
+	if x > 10 {
+		panic(x)
+	}
+	return
+}
+
+var namedSeen int
+
+func namedDeferRead(x int) (n int) {
+	defer func() { namedSeen = n }()
+	return x
+}
+
+func namedClosure() (n int) {
+	inc := func() { n++ }
+	inc()
+	inc()
+	return
+}
+
+func testNamedZero() string {
+	n, s := namedZero()
+	if n == 0 && s == "" {
+		return "ok"
+	}
+	return "fail"
+}
+
+func testNamedNaked(x int) int {
+	n, ok := namedNaked(x)
+	if !ok {
+		return -1
+	}
+	return n
+}
+
+func testNamedExplicit(x int) int {
+	return namedExplicit(x)
+}
+
+func testNamedSwap() string {
+	x, y := namedSwap("a", "b")
+	return x + y
+}
+
+func testNamedDeferModify(x int) int {
+	return namedDeferModify(x)
+}
+
+func testNamedRecover(x int) int {
+	n, failed := namedRecover(x)
+	if failed {
+		return -n
+	}
+	return n
+}
+
+func testNamedDeferRead(x int) int {
+	namedDeferRead(x)
+	return namedSeen
+}
+
+func testNamedClosure() int {
+	return namedClosure()
+}
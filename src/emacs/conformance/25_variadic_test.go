@@ -0,0 +1,118 @@
+package conformance
+
+import (
+	"emacs/lisp"
+)
+
+func variadicSum(xs ...int) int {
+	total := 0
+	for _, x := range xs {
+		total += x
+	}
+	return total
+}
+
+func variadicJoin(sep string, parts ...string) string {
+	res := ""
+	for i, part := range parts {
+		if i != 0 {
+			res += sep
+		}
+		res += part
+	}
+	return res
+}
+
+// VariadicJoin is called from Lisp with "&rest" arguments.
+func VariadicJoin(sep string, parts ...string) string {
+	return variadicJoin(sep, parts...)
+}
+
+func variadicSet(xs ...int) {
+	xs[0] = 100
+}
+
+type variadicAcc struct{ total int }
+
+func (acc *variadicAcc) add(xs ...int) {
+	acc.total += variadicSum(xs...)
+}
+
+func testVariadicCall() int {
+	return variadicSum() + variadicSum(1) + variadicSum(2, 3, 4)
+}
+
+func testVariadicSpread() int {
+	xs := []int{1, 2, 3}
+	return variadicSum(xs...)
+}
+
+func testVariadicSpreadAlias() int {
+	xs := []int{1, 2, 3}
+	variadicSet(xs...)
+	return xs[0]
+}
+
+func testVariadicMixed() string {
+	parts := []string{"a", "b", "c"}
+	return variadicJoin("-", parts...) + variadicJoin(",", "x", "y")
+}
+
+func testVariadicMethod() int {
+	acc := &variadicAcc{}
+	acc.add(1, 2)
+	acc.add([]int{3, 4}...)
+	return acc.total
+}
+
+func testVariadicFuncValue() int {
+	f := variadicSum
+	xs := []int{5, 5}
+	return f(1, 2) + f(xs...)
+}
+
+func testAppendMulti() int {
+	xs := append([]int{1}, 2, 3, 4)
+	return len(xs) + xs[3]
+}
+
+func testAppendSlice() int {
+	xs := []int{1, 2}
+	ys := []int{3, 4, 5}
+	xs = append(xs, ys...)
+	return len(xs)*10 + xs[4]
+}
+
+func testAppendSliceInPlace() int {
+	xs := make([]int, 2, 10)
+	ys := append(xs, 1, 2)
+	zs := append(xs[:1], ys[2:]...)
+	return zs[1]*10 + ys[1]
+}
+
+func testAppendSelf() int {
+	xs := []int{1, 2, 3}
+	xs = append(xs, xs...)
+	return len(xs)*10 + xs[5]
+}
+
+func testAppendString() string {
+	bs := []byte("ab")
+	bs = append(bs, "cd"...)
+	return string(bs)
+}
+
+func testLispSpread() string {
+	args := []interface{}{"b", "c"}
+	return lisp.Call("concat", append([]interface{}{"a"}, args...)...).String()
+}
+
+func testLispSpreadFFI() int {
+	xs := []int{3, 1, 2}
+	return lisp.MinInt(xs...)
+}
+
+func testVariadicExported() string {
+	parts := []string{"b", "c"}
+	return VariadicJoin("-", "a") + VariadicJoin(",", parts...)
+}
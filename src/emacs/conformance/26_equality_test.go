@@ -0,0 +1,211 @@
+package conformance
+
+import (
+	"emacs/lisp"
+)
+
+type eqPoint struct{ x, y int }
+
+type eqNode struct {
+	name string
+	next *eqNode
+}
+
+type eqMixed struct {
+	f   float64
+	ptr *int
+	s   shape
+}
+
+type eqKey struct {
+	name string
+	pos  [2]int
+}
+
+func testEqStruct() string {
+	a, b, c := eqPoint{x: 1, y: 2}, eqPoint{x: 1, y: 2}, eqPoint{x: 2, y: 1}
+	res := ""
+	if a == b {
+		res += "a"
+	}
+	if a != c {
+		res += "b"
+	}
+	return res
+}
+
+func testEqStructPtrField() string {
+	tail1, tail2 := &eqNode{name: "t"}, &eqNode{name: "t"}
+	a := eqNode{name: "a", next: tail1}
+	b := eqNode{name: "a", next: tail1}
+	c := eqNode{name: "a", next: tail2}
+	res := ""
+	if a == b {
+		res += "a"
+	}
+	if a != c {
+		res += "b"
+	}
+	return res
+}
+
+func testEqStructMixed() string {
+	n := 1
+	sq := &square{side: 1}
+	a := eqMixed{f: 0.0, ptr: &n, s: sq}
+	b := eqMixed{f: -a.f, ptr: &n, s: sq}
+	c := eqMixed{f: 0.0, ptr: &n, s: &square{side: 1}}
+	res := ""
+	if a == b {
+		res += "a"
+	}
+	if a != c {
+		res += "b"
+	}
+	return res
+}
+
+func testEqArray() string {
+	a := [3]int{1, 2, 3}
+	b := [3]int{1, 2, 3}
+	c := [2]float64{1, 2}
+	d := [2]float64{1, 3}
+	res := ""
+	if a == b {
+		res += "a"
+	}
+	if c != d {
+		res += "b"
+	}
+	return res
+}
+
+func testEqIface() string {
+	sq := &square{side: 1}
+	var s1, s2 shape = sq, sq
+	var s3 shape = &square{side: 1}
+	var s4 shape
+	res := ""
+	if s1 == s2 {
+		res += "a"
+	}
+	if s1 != s3 {
+		res += "b"
+	}
+	if s4 == nil && s1 != nil {
+		res += "c"
+	}
+	if s1 == sq {
+		res += "d"
+	}
+	return res
+}
+
+func testEqNilRaw() string {
+	var x interface{}
+	var o lisp.Object
+	res := ""
+	if x == nil {
+		res += "a"
+	}
+	if o == nil {
+		res += "b"
+	}
+	x = 0
+	if x != nil {
+		res += "c"
+	}
+	return res
+}
+
+func testEqEmptyIface() string {
+	var a, b interface{} = 1, 1
+	var c interface{} = 1.0
+	var d interface{} = "x"
+	res := ""
+	if a == b {
+		res += "a"
+	}
+	if a != c {
+		res += "b"
+	}
+	if d == "x" {
+		res += "c"
+	}
+	return res
+}
+
+func testEqBoolString() string {
+	t, f := true, false
+	s := "a"
+	res := ""
+	if t != f && t == true {
+		res += "a"
+	}
+	if s != "b" {
+		res += "b"
+	}
+	return res
+}
+
+func testMapStructKey() int {
+	m := make(map[eqKey]int)
+	m[eqKey{name: "a", pos: [2]int{1, 2}}] = 10
+	m[eqKey{name: "a", pos: [2]int{1, 2}}] += 5
+	m[eqKey{name: "b", pos: [2]int{1, 2}}] = 1
+	return len(m)*100 + m[eqKey{name: "a", pos: [2]int{1, 2}}]
+}
+
+func testMapMixedKey() int {
+	p, s := new(int), &square{side: 1}
+	zero := 0.0
+	m := make(map[eqMixed]int)
+	m[eqMixed{f: zero, ptr: p, s: s}] = 1
+	m[eqMixed{f: -zero, ptr: p, s: s}] += 10
+	m[eqMixed{f: zero, ptr: new(int), s: s}] = 100
+	return len(m)*1000 + m[eqMixed{ptr: p, s: s}]
+}
+
+func testMapArrayKey() int {
+	a, b := new(int), new(int)
+	m := map[[2]*int]int{{a, b}: 1}
+	m[[2]*int{a, b}] += 1
+	m[[2]*int{b, a}] = 5
+	return len(m)*10 + m[[2]*int{a, b}]
+}
+
+func testMapFloatKey() int {
+	m := make(map[float64]int)
+	zero := 0.0
+	m[zero] = 1
+	m[-zero] += 1
+	m[1] = 10
+	one := 1.0
+	return m[one] + m[0]
+}
+
+func testMapPtrKey() int {
+	a, b := &eqPoint{x: 1, y: 2}, &eqPoint{x: 1, y: 2}
+	m := make(map[*eqPoint]int)
+	m[a] = 1
+	m[b] = 2
+	return len(m)*10 + m[a]
+}
+
+func testMapIfaceKey() int {
+	a, b := &square{side: 1}, &square{side: 1}
+	m := make(map[shape]int)
+	m[a] = 1
+	m[b] = 2
+	m[a] += 10
+	return len(m)*100 + m[a]
+}
+
+func testMapEmptyIfaceKey() int {
+	m := make(map[interface{}]int)
+	m[1] = 1
+	m[1.0] = 2
+	m["x"] = 3
+	m["x"] += 10
+	return len(m)*100 + m["x"]
+}
@@ -0,0 +1,193 @@
+package conformance
+
+func testBreakLabel(n int) int {
+	count := 0
+outer:
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i*j == 6 {
+				break outer
+			}
+			count++
+		}
+	}
+	return count
+}
+
+func testContinueLabel(n int) int {
+	count := 0
+outer:
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if j > i {
+				continue outer
+			}
+			count++
+		}
+	}
+	return count
+}
+
+func testContinueLabelRange() string {
+	res := ""
+rows:
+	for _, row := range []string{"ab", "cd", "ef"} {
+		for i := range row {
+			if row[i] == 'd' {
+				continue rows
+			}
+			res += row[i : i+1]
+		}
+		res += ";"
+	}
+	return res
+}
+
+func testContinueLabelLocals(n int) int {
+	total := 0
+outer:
+	for j := 0; j < n; j++ {
+		for i := 0; i < j; i++ {
+			if i == 3 {
+				continue outer
+			}
+		}
+		a := j
+		b := a + 1
+		c := b + 1
+		total += a + b + c
+	}
+	return total
+}
+
+func testBreakSwitch(n int) int {
+	count := 0
+	for i := 0; i < n; i++ {
+		switch {
+		case i%2 == 0:
+			if i > 4 {
+				break
+			}
+			count += 10
+		default:
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+func testBreakLabeledSwitch(n int) int {
+	count := 0
+sw:
+	switch n {
+	case 10:
+		for {
+			count++
+			if count == 3 {
+				break sw
+			}
+		}
+	}
+	return count
+}
+
+func testBreakTypeSwitch(x interface{}) string {
+	res := ""
+	for i := 0; i < 2; i++ {
+		switch x.(type) {
+		case int:
+			res += "i"
+			break
+		case string:
+			res += "s"
+		}
+		res += "."
+	}
+	return res
+}
+
+func testBreakSelect() int {
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+	sum := 0
+	for i := 0; i < 3; i++ {
+		select {
+		case x := <-ch:
+			if x == 2 {
+				break
+			}
+			sum += x
+		}
+	}
+	return sum
+}
+
+func testBreakRangeArray() int {
+	count := 0
+	var xs [5]int
+	for range xs {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	return count
+}
+
+func fallthroughChain(n int) string {
+	res := ""
+	switch n {
+	case 0:
+		res += "0"
+		fallthrough
+	case 1:
+		res += "1"
+		fallthrough
+	default:
+		res += "d"
+		fallthrough
+	case 2:
+		res += "2"
+		fallthrough
+	case 3:
+		res += "3"
+	}
+	return res
+}
+
+func testFallthrough() string {
+	return fallthroughChain(0) + " " +
+		fallthroughChain(1) + " " +
+		fallthroughChain(2) + " " +
+		fallthroughChain(3) + " " +
+		fallthroughChain(4)
+}
+
+func testFallthroughDefault(n int) string {
+	res := ""
+	switch {
+	default:
+		res += "d"
+		fallthrough
+	case n > 10:
+		res += "big"
+	}
+	return res
+}
+
+func multiCase(s string) int {
+	switch s {
+	case "a", "b":
+		return 1
+	case "c", "d", "e":
+		return 2
+	}
+	return 0
+}
+
+func testMultiCase() int {
+	return multiCase("a")*1000 + multiCase("b")*100 + multiCase("e")*10 + multiCase("x")
+}
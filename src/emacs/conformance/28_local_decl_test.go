@@ -0,0 +1,135 @@
+package conformance
+
+func testLocalConst(n int) int {
+	const maxDepth = 8
+	const (
+		a = iota * 10
+		b
+		c
+	)
+	return n*maxDepth + c
+}
+
+func testLocalConstTyped() float64 {
+	const half float64 = 1 / 2.0
+	return half * 3
+}
+
+func testLocalStruct(n int) int {
+	type point struct {
+		x, y int
+	}
+	p := point{x: n, y: 2}
+	q := &point{1, 1}
+	q.x += p.x
+	return q.x * p.y
+}
+
+func testLocalAlias(n int) int {
+	type integer = int
+	var x integer = n
+	return x + 1
+}
+
+// Values of named interface types carry dynamic type tag.
+type tagged interface{}
+
+type localNamer interface {
+	name() string
+}
+
+func (s *square) name() string { return "square" }
+
+func localIface() tagged {
+	type box struct{ v int }
+	return box{1}
+}
+
+func localIface2() tagged {
+	type box struct{ v int }
+	return box{2}
+}
+
+func testLocalTypeIdentity() string {
+	res := ""
+	type box struct{ v int }
+	if _, ok := localIface().(box); !ok {
+		res += "a"
+	}
+	x := localIface2()
+	if x != localIface() {
+		res += "b"
+	}
+	return res
+}
+
+func testLocalIfaceType(n int) int {
+	type areaer interface {
+		area() int
+	}
+	var x areaer = &square{side: n}
+	if _, ok := x.(localNamer); ok {
+		return x.area()
+	}
+	return -1
+}
+
+func testLocalTypeSwitch() string {
+	type level int
+	type kind int
+	var x tagged = kind(1)
+	switch x.(type) {
+	case level:
+		return "level"
+	case kind:
+		return "kind"
+	}
+	return "other"
+}
+
+func (s *square) localLevel() tagged {
+	type level int
+	return level(1)
+}
+
+func localLevel() tagged {
+	type level int
+	return level(1)
+}
+
+var localLevelLit = func() tagged {
+	type level int
+	return level(1)
+}
+
+var localLevelLit2 = func() tagged {
+	type level int
+	return level(1)
+}
+
+func testLocalTypeBlocks() string {
+	var xs []tagged
+	{
+		type level int
+		xs = append(xs, level(1))
+	}
+	{
+		type level int
+		xs = append(xs, level(1))
+	}
+	res := ""
+	if xs[0] != xs[1] && xs[1] == xs[1] {
+		res += "a"
+	}
+	type level int
+	if _, ok := xs[1].(level); !ok {
+		res += "b"
+	}
+	if (&square{}).localLevel() != localLevel() && localLevel() == localLevel() {
+		res += "c"
+	}
+	if localLevelLit() != localLevelLit2() && localLevelLit() == localLevelLit() {
+		res += "d"
+	}
+	return res
+}
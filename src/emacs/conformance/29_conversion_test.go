@@ -0,0 +1,103 @@
+package conformance
+
+type wirePoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type domainPoint struct {
+	X int
+	Y int
+}
+
+type celsius float64
+type tally int
+type label string
+type labels []string
+
+func testConvStruct(n int) int {
+	w := wirePoint{X: n, Y: 2}
+	d := domainPoint(w)
+	d.X++
+	return w.X*100 + d.X*10 + d.Y
+}
+
+func testConvStructPtr(n int) int {
+	w := &wirePoint{X: n}
+	d := (*domainPoint)(w)
+	d.X++
+	return w.X
+}
+
+func testConvNamed(n int) int {
+	c := tally(n)
+	c++
+	return int(c) * 2
+}
+
+func testConvNamedString() string {
+	l := label("a")
+	ls := labels{"b", "c"}
+	return string(l) + []string(ls)[1]
+}
+
+func testConvIntToFloat(n int) float64 {
+	return float64(n) / 4
+}
+
+func testConvNamedFloat(n int) celsius {
+	return celsius(n) + 0.5
+}
+
+func testConvFloatToInt(x float64) int {
+	return int(x)
+}
+
+func testConvFloatToUint8(x float64) uint8 {
+	return uint8(x)
+}
+
+func testConvIntToUint8(n int) uint8 {
+	return uint8(n)
+}
+
+func testConvIntToUint16(n int) int {
+	return int(uint16(n))
+}
+
+func testConvIntToUint64(n int) uint64 {
+	return uint64(n)
+}
+
+func testConvUint64Elem(n int) uint64 {
+	var arr [1]uint64
+	arr[0] = uint64(n) + 1
+	return arr[0]
+}
+
+func testConvRune(n int) string {
+	return string(rune(n))
+}
+
+func testConvRuneInvalid() string {
+	n := -1
+	return string(rune(n))
+}
+
+func testConvRunes(s string) int {
+	runes := []rune(s)
+	runes[0] = 'x'
+	return len(runes)*10 + len(s)
+}
+
+func testConvRunesToString(s string) string {
+	runes := []rune(s)
+	runes[1] = 'λ'
+	return string(runes)
+}
+
+func testConvBytes(s string) string {
+	b := []byte(s)
+	b[0] = 'x'
+	return string(b)
+}
@@ -0,0 +1,116 @@
+package conformance
+
+type calculator interface {
+	sum5(a, b, c, d, e int) int
+	sum4(a, b, c, d int) int
+	sumAll(base int, xs ...int) int
+	divmod(a, b int) (int, int)
+	join(sep string, parts ...string) string
+}
+
+type simpleCalc struct {
+	scale int
+}
+
+func (c *simpleCalc) sum5(a, b, c2, d, e int) int {
+	return (a + b + c2 + d + e) * c.scale
+}
+
+func (c *simpleCalc) sum4(a, b, c2, d int) int {
+	return (a + b + c2 + d) * c.scale
+}
+
+func (c *simpleCalc) sumAll(base int, xs ...int) int {
+	for _, x := range xs {
+		base += x
+	}
+	return base * c.scale
+}
+
+func (c *simpleCalc) divmod(a, b int) (int, int) {
+	return a / b, a % b
+}
+
+func (c *simpleCalc) join(sep string, parts ...string) string {
+	res := ""
+	for i, p := range parts {
+		if i != 0 {
+			res += sep
+		}
+		res += p
+	}
+	return res
+}
+
+type argNamer interface {
+	name() string
+}
+
+type argName string
+
+func (n argName) name() string { return string(n) }
+
+type argPoint struct{ x int }
+
+type argSink interface {
+	put(p argPoint, n argNamer) string
+	putWide(a, b, c, d int, p argPoint, n argNamer) string
+}
+
+type pointSink struct{ tag string }
+
+func (s pointSink) put(p argPoint, n argNamer) string {
+	p.x = 10
+	return s.tag + n.name()
+}
+
+func (s pointSink) putWide(a, b, c, d int, p argPoint, n argNamer) string {
+	p.x = a + b + c + d
+	return s.tag + n.name()
+}
+
+func newCalc(scale int) calculator {
+	return &simpleCalc{scale: scale}
+}
+
+func testIfaceCallWide(n int) int {
+	c := newCalc(n)
+	return c.sum5(1, 2, 3, 4, 5) + c.sum4(1, 1, 1, 1)
+}
+
+func testIfaceCallVariadic() int {
+	c := newCalc(1)
+	xs := []int{10, 20}
+	return c.sumAll(1) + c.sumAll(1, 2, 3) + c.sumAll(100, xs...)
+}
+
+func testIfaceCallMultiResult() int {
+	c := newCalc(1)
+	q, r := c.divmod(17, 5)
+	return q*10 + r
+}
+
+func testIfaceCallVariadicString() string {
+	c := newCalc(1)
+	return c.join("-", "a", "b", "c") + c.join(",")
+}
+
+func testIfaceMethodValueWide() int {
+	f := newCalc(2).sum5
+	return f(1, 1, 1, 1, 1)
+}
+
+func testIfaceCallArgs() string {
+	p := argPoint{x: 1}
+	var s argSink = pointSink{}
+	res := s.put(p, argName("a")) + s.putWide(1, 2, 3, 4, p, argName("b"))
+	if p.x == 1 {
+		res += "c"
+	}
+	return res
+}
+
+func testMethodIfaceArg() string {
+	s := pointSink{tag: "-"}
+	return s.put(argPoint{}, argName("a")) + s.putWide(1, 2, 3, 4, argPoint{}, argName("b"))
+}
@@ -0,0 +1,125 @@
+package conformance
+
+func return10(n int) (int, int, int, int, int, int, int, int, int, int) {
+	return n, n + 1, n + 2, n + 3, n + 4, n + 5, n + 6, n + 7, n + 8, n + 9
+}
+
+func return9Named(n int) (a, b, c, d, e, f, g, h int, s string) {
+	a, h = n, n*2
+	s = "s"
+	return
+}
+
+func return9Defer(n int) (int, int, int, int, int, int, int, int, int) {
+	defer func() {}()
+	return 1, 2, 3, 4, 5, 6, 7, 8, n
+}
+
+func return10Forward(n int) (int, int, int, int, int, int, int, int, int, int) {
+	return return10(n)
+}
+
+func testMultiRet10(n int) int {
+	a, _, _, _, _, _, f, g, h, j := return10(n)
+	return a + f + g + h + j
+}
+
+func testMultiRetNamed9(n int) string {
+	a, _, _, _, _, _, _, h, s := return9Named(n)
+	if a == n && h == n*2 {
+		return s
+	}
+	return "fail"
+}
+
+func testMultiRetDefer9(n int) int {
+	_, b, _, _, _, _, _, h, i := return9Defer(n)
+	return b + h + i
+}
+
+func testMultiRetForward(n int) int {
+	_, _, _, _, _, _, _, h, i, j := return10Forward(n)
+	return h + i + j
+}
+
+// fibPair returns (fib(n), fib(n+1)) recursively.
+func fibPair(n int) (int, int) {
+	if n == 0 {
+		return 0, 1
+	}
+	a, b := fibPair(n - 1)
+	return b, a + b
+}
+
+func testMultiRetRecursive(n int) int {
+	a, b := fibPair(n)
+	return a*1000 + b
+}
+
+func pairIndex(n int) (int, int) {
+	return n, n * 10
+}
+
+func testMultiRetNested() int {
+	xs := []int{0, 0, 0}
+	var p, q int
+	// Index expression calls multi-valued function
+	// after the right hand side is evaluated.
+	xs[pairFirst(2)], q = pairIndex(5)
+	p, _ = pairIndex(7)
+	return xs[2]*100 + q + p
+}
+
+func pairFirst(n int) int {
+	a, b := pairIndex(n)
+	return a + b - n*10
+}
+
+func triple(n int) (int, int, int) {
+	return n, n + 1, n + 2
+}
+
+// returnDeferCalls calls multi-valued function from deferred
+// closure after its own results are evaluated.
+func returnDeferCalls(n int) (a, b int) {
+	defer func() {
+		x, y, z := triple(a)
+		b += x + y + z
+	}()
+	return n, n * 2
+}
+
+func testMultiRetDeferCalls(n int) int {
+	a, b := returnDeferCalls(n)
+	return a*1000 + b
+}
+
+var multiRetA, multiRetB = pairIndex(4)
+
+func testMultiRetGlobal() int {
+	return multiRetA*100 + multiRetB
+}
+
+func pairIface(n int) (interface{}, interface{}) {
+	return pairIndex(n)
+}
+
+func testMultiRetForwardIface(n int) int {
+	x, y := pairIface(n)
+	return x.(int) + y.(int)
+}
+
+// sumTriples recursively unpacks vector results of itself.
+func sumTriples(n int) (int, int, int) {
+	if n == 0 {
+		return 0, 0, 0
+	}
+	a, b, c := sumTriples(n - 1)
+	x, y, z := triple(n)
+	return a + x, b + y, c + z
+}
+
+func testMultiRetRecursiveVector(n int) int {
+	a, b, c := sumTriples(n)
+	return a*10000 + b*100 + c
+}
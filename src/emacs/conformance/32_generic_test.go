@@ -0,0 +1,161 @@
+package conformance
+
+type number interface {
+	~int | ~float64
+}
+
+func mapSlice[T, U any](xs []T, f func(T) U) []U {
+	res := make([]U, 0, len(xs))
+	for _, x := range xs {
+		res = append(res, f(x))
+	}
+	return res
+}
+
+func filter[T any](xs []T, keep func(T) bool) []T {
+	var res []T
+	for _, x := range xs {
+		if keep(x) {
+			res = append(res, x)
+		}
+	}
+	return res
+}
+
+func sumOf[T number](xs []T) T {
+	var total T
+	for _, x := range xs {
+		total += x
+	}
+	return total
+}
+
+func indexOf[T comparable](xs []T, x T) int {
+	for i := range xs {
+		if xs[i] == x {
+			return i
+		}
+	}
+	return -1
+}
+
+func maxOf[T number](a, b T) T {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// countIf calls another generic function.
+func countIf[T any](xs []T, keep func(T) bool) int {
+	return len(filter(xs, keep))
+}
+
+type pair[K, V comparable] struct {
+	key K
+	val V
+}
+
+func (p pair[K, V]) swap() pair[V, K] {
+	return pair[V, K]{key: p.val, val: p.key}
+}
+
+func (p *pair[K, V]) setVal(val V) {
+	p.val = val
+}
+
+type stack[T any] struct {
+	items []T
+}
+
+func (s *stack[T]) push(x T) {
+	s.items = append(s.items, x)
+}
+
+func (s *stack[T]) pop() (T, bool) {
+	var zero T
+	if len(s.items) == 0 {
+		return zero, false
+	}
+	x := s.items[len(s.items)-1]
+	s.items = s.items[:len(s.items)-1]
+	return x, true
+}
+
+func (s *stack[T]) size() int {
+	return len(s.items)
+}
+
+func (s *stack[T]) describe(n argNamer) string {
+	return n.name() + string(rune('0'+len(s.items)))
+}
+
+func testGenericMap() int {
+	lens := mapSlice([]string{"a", "bb", "ccc"}, func(s string) int {
+		return len(s)
+	})
+	return lens[0]*100 + lens[1]*10 + lens[2]
+}
+
+func testGenericFilter() string {
+	words := filter[string]([]string{"go", "lisp", "c", "elisp"}, func(s string) bool {
+		return len(s) > 2
+	})
+	return words[0] + words[1]
+}
+
+func testGenericSum() float64 {
+	return float64(sumOf([]int{1, 2, 3})) + sumOf([]float64{0.5, 0.25})
+}
+
+func testGenericIndex() int {
+	return indexOf([]string{"a", "b", "c"}, "c")*10 + indexOf([]int{1, 2}, 5)
+}
+
+func testGenericFuncValue() int {
+	f := maxOf[int]
+	return f(3, 7) + f(2, -2)
+}
+
+func testGenericNested() int {
+	return countIf([]int{1, 2, 3, 4, 5}, func(x int) bool { return x%2 == 1 })
+}
+
+func testGenericStruct() string {
+	p := pair[string, int]{key: "x", val: 1}
+	p.setVal(5)
+	q := p.swap()
+	return q.val + string(rune('0'+q.key))
+}
+
+func testGenericStack() int {
+	var s stack[int]
+	s.push(1)
+	s.push(2)
+	x, _ := s.pop()
+	_, ok := s.pop()
+	_, empty := s.pop()
+	if ok && !empty {
+		return x
+	}
+	return -1
+}
+
+func testGenericIface() int {
+	s := &stack[string]{}
+	s.push("a")
+	var sz sizer = s
+	if _, ok := sz.(*stack[string]); !ok {
+		return -1
+	}
+	if _, ok := sz.(*stack[int]); ok {
+		return -2
+	}
+	return sz.size()
+}
+
+func testGenericMethodIfaceArg() string {
+	s := &stack[int]{}
+	s.push(1)
+	return s.describe(argName("n"))
+}
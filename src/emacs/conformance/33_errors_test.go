@@ -0,0 +1,192 @@
+package conformance
+
+import (
+	"emacs/errors"
+	"emacs/lisp"
+)
+
+var errNotFound = errors.New("not found")
+
+type pathError struct {
+	op   string
+	path string
+	err  error
+}
+
+func (e *pathError) Error() string {
+	return e.op + " " + e.path + ": " + e.err.Error()
+}
+
+func (e *pathError) Unwrap() error {
+	return e.err
+}
+
+// timeoutError matches any other timeoutError in errors.Is.
+type timeoutError struct {
+	sec int
+}
+
+func (e timeoutError) Error() string {
+	return "timeout"
+}
+
+func (e timeoutError) Is(target error) bool {
+	_, ok := target.(timeoutError)
+	return ok
+}
+
+func openFile(path string) error {
+	if path == "" {
+		return &pathError{op: "open", path: path, err: errNotFound}
+	}
+	return nil
+}
+
+func readFile(path string) (string, error) {
+	if err := openFile(path); err != nil {
+		return "", errors.Errorf("read: %w", err)
+	}
+	return "data", nil
+}
+
+func testErrorNil() string {
+	res := ""
+	if err := openFile("a"); err == nil {
+		res += "nil "
+	}
+	if data, err := readFile("a"); err == nil {
+		res += data + " "
+	}
+	var err error
+	if _, ok := err.(*pathError); !ok && err == nil {
+		res += "zero "
+	}
+	if e, ok := errNotFound.(*pathError); !ok && e == nil {
+		res += "assert "
+	}
+	if w, ok := errNotFound.(sizer); !ok && w == nil {
+		res += "iface"
+	}
+	return res
+}
+
+func testErrorsNew() string {
+	a1, a2 := errors.New("a"), errors.New("a")
+	if a1 == a2 || a1 != a1 {
+		return "bad identity"
+	}
+	return a1.Error() + errNotFound.Error()
+}
+
+func testErrorfWrap() string {
+	_, err := readFile("")
+	if errors.Unwrap(errors.Unwrap(err)) != errNotFound {
+		return "bad unwrap"
+	}
+	if !errors.Is(err, errNotFound) || errors.Is(err, errors.New("not found")) {
+		return "bad is"
+	}
+	return err.Error()
+}
+
+func testErrorsAs() string {
+	_, err := readFile("")
+	var pe *pathError
+	if !errors.As(err, &pe) {
+		return "not found"
+	}
+	var te timeoutError
+	if errors.As(err, &te) {
+		return "bad as"
+	}
+	return pe.op + ":" + pe.err.Error()
+}
+
+func testErrorsIsMethod() bool {
+	err := errors.Errorf("dial: %w", error(timeoutError{sec: 1}))
+	return errors.Is(err, timeoutError{sec: 2}) &&
+		!errors.Is(err, errNotFound) &&
+		!errors.Is(nil, errNotFound) &&
+		errors.Is(nil, nil)
+}
+
+func testErrorfConcrete() bool {
+	pe := &pathError{"open", "f", errNotFound}
+	err := errors.Errorf("read: %w", pe)
+	var target *pathError
+	return errors.As(err, &target) && target == pe
+}
+
+func testErrorfVerbs() string {
+	return errors.Errorf("%d|%v|%q|%t|%5.2f|%x|%%|%s", 10, "v", "q", true, 1.5, 255, errNotFound).Error()
+}
+
+func testErrorfMulti() string {
+	var timeout error = timeoutError{sec: 1}
+	err := errors.Errorf("%w, %w", errNotFound, timeout)
+	if !errors.Is(err, errNotFound) || !errors.Is(err, timeout) {
+		return "bad is"
+	}
+	if errors.Unwrap(err) != nil {
+		return "bad unwrap"
+	}
+	return err.Error()
+}
+
+type multiError []error
+
+func (m multiError) Error() string   { return "multi" }
+func (m multiError) Unwrap() []error { return m }
+
+func testErrorsUnwrapMulti() bool {
+	var timeout error = timeoutError{sec: 1}
+	err := errors.Errorf("wrap: %w", multiError{errNotFound, timeout})
+	return errors.Is(err, errNotFound) && errors.Is(err, timeout) &&
+		errors.Unwrap(errors.Unwrap(err)) == nil
+}
+
+func testErrorRecover() (res string) {
+	defer func() {
+		r := recover()
+		if err, ok := r.(error); ok && errors.Is(err, errNotFound) {
+			res = "recovered: " + err.Error()
+		}
+	}()
+	panic(errNotFound)
+}
+
+func testErrorTypeSwitch() string {
+	res := ""
+	for _, x := range []interface{}{1, errNotFound, "s", error(&pathError{"stat", "f", errNotFound})} {
+		switch x := x.(type) {
+		case error:
+			res += "[" + x.Error() + "]"
+		case string:
+			res += x
+		default:
+			res += "?"
+		}
+	}
+	return res
+}
+
+func testErrorMessage() string {
+	_, err := readFile("")
+	return errors.Message(lisp.Call("identity", err)) + errors.Message(lisp.Call("identity", 1))
+}
+
+// errorCondition returns condition that is signaled by errors.Check.
+func errorCondition(interactive bool) (res string) {
+	defer func() {
+		e := lisp.Call("identity", recover())
+		res = lisp.Call("symbol-name", lisp.Call("car", e)).String() +
+			":" + lisp.Call("error-message-string", e).String()
+	}()
+	errors.Check(nil, interactive)
+	errors.Check(errNotFound, interactive)
+	return "unreachable"
+}
+
+func testErrorCheck() string {
+	return errorCondition(false) + " " + errorCondition(true)
+}
@@ -0,0 +1,10 @@
+// Package initdep is imported by conformance tests
+// to check imported packages initialization.
+package initdep
+
+// InitCount is incremented by every "init" call.
+var InitCount int
+
+func init() {
+	InitCount++
+}
@@ -0,0 +1,4 @@
+// Package pairwise results are compared against gc compiled code,
+// so exact integer overflow semantics are requested.
+//goism:wraparound
+package pairwise
@@ -0,0 +1,137 @@
+package pairwise
+
+func testInt8Inc() int8 {
+	x := int8(127)
+	x++
+	return x
+}
+
+func testInt8Mul() int8 {
+	x := int8(100)
+	return x * 3
+}
+
+func testInt16Sub() int16 {
+	x := int16(-32768)
+	return x - 1
+}
+
+func testInt32Add() int32 {
+	x := int32(2147483647)
+	return x + 10
+}
+
+func testInt32Neg() int32 {
+	x := int32(-2147483648)
+	return -x
+}
+
+func testInt64Add() int64 {
+	x := int64(9223372036854775807)
+	return x + 1
+}
+
+func testInt64Shl() int64 {
+	x := int64(3)
+	x <<= 62
+	return x
+}
+
+func testUint8Dec() uint8 {
+	var x uint8
+	x--
+	return x
+}
+
+func testUint8Not() uint8 {
+	x := uint8(0x0F)
+	return ^x
+}
+
+func testUint16Shl() uint16 {
+	x := uint16(0xABCD)
+	return x << 4
+}
+
+func testUint32Mul() uint32 {
+	x := uint32(0xDEADBEEF)
+	return x * 31
+}
+
+func testUint32Neg() uint32 {
+	x := uint32(1)
+	return -x
+}
+
+func testUint64Add() uint64 {
+	x := uint64(1 << 63)
+	return x + x + 5
+}
+
+func testUint64Sub() uint64 {
+	var x uint64
+	return x - 1
+}
+
+func testUint64Not() uint64 {
+	x := uint64(1)
+	return ^x
+}
+
+func testUintNot() uint {
+	x := uint(5)
+	return ^x
+}
+
+func testUintptrNot() uintptr {
+	x := uintptr(0)
+	return ^x
+}
+
+func testUint64Const() uint64 {
+	x := uint64(18446744073709551615)
+	return x / 3
+}
+
+func testConvTruncate() int {
+	x := 1000
+	return int(uint8(x)) + int(int8(x)) + int(uint16(-x))
+}
+
+func testConvSign() int64 {
+	x := uint64(18446744073709551615)
+	return int64(x)
+}
+
+func testFNV32() uint32 {
+	h := uint32(2166136261)
+	for _, c := range []byte("hello, world") {
+		h ^= uint32(c)
+		h *= 16777619
+	}
+	return h
+}
+
+func testFNV64() uint64 {
+	h := uint64(14695981039346656037)
+	for _, c := range []byte("hello, world") {
+		h ^= uint64(c)
+		h *= 1099511628211
+	}
+	return h
+}
+
+func testCRC32() uint32 {
+	crc := ^uint32(0)
+	for _, c := range []byte("hello, world") {
+		crc ^= uint32(c)
+		for i := 0; i < 8; i++ {
+			if crc&1 == 1 {
+				crc = (crc >> 1) ^ 0xEDB88320
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return ^crc
+}
@@ -0,0 +1,290 @@
+// Package errors provides a subset of Go "errors" package
+// along with "fmt.Errorf" and helpers that report Go errors
+// as Emacs Lisp conditions.
+package errors
+
+import (
+	"emacs/lisp"
+)
+
+// New returns an error that formats as the given text.
+// Each call to New returns a distinct error value even if the text is identical.
+func New(text string) error {
+	return &errorString{s: text}
+}
+
+type errorString struct {
+	s string
+}
+
+func (e *errorString) Error() string {
+	return e.s
+}
+
+// Errorf formats according to a format specifier and returns
+// the string as a value that satisfies error.
+// Formatting is done by Emacs Lisp "format" function;
+// Go-specific verbs are translated: %v and %w become %s,
+// %q becomes %S and %t prints "true" or "false".
+// Operands that hold error values are printed as their Error() result.
+// If the format specifier includes a %w verb with an error operand,
+// the returned error implements an Unwrap method returning the operand.
+// If there is more than one %w verb, the returned error wraps all
+// of them; Is and As examine them in the order they appear.
+func Errorf(format string, a ...any) error {
+	var wrapped []error
+	spec := make([]byte, 0, len(format))
+	args := make([]any, 1, len(a)+1)
+	n := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			spec = append(spec, format[i])
+			continue
+		}
+		// Flags, width and precision are passed as is.
+		j := i + 1
+		for j < len(format) && isFlag(format[j]) {
+			j++
+		}
+		if j == len(format) {
+			spec = append(spec, "%%!(NOVERB)"...)
+			break
+		}
+		flags, verb := format[i+1:j], format[j]
+		i = j
+		if verb == '%' {
+			spec = append(spec, "%%"...)
+			continue
+		}
+		if n == len(a) {
+			spec = append(spec, "%%!"...)
+			spec = append(spec, verb)
+			spec = append(spec, "(MISSING)"...)
+			continue
+		}
+
+		arg := a[n]
+		n++
+		if err, ok := arg.(error); ok {
+			if verb == 'w' {
+				wrapped = append(wrapped, err)
+			}
+			arg = err.Error()
+		}
+		switch verb {
+		case 'v', 'w':
+			verb = 's'
+		case 'q':
+			verb = 'S'
+		case 't':
+			verb = 's'
+			if b, ok := arg.(bool); ok {
+				arg = boolString(b)
+			}
+		}
+		spec = append(spec, '%')
+		spec = append(spec, flags...)
+		spec = append(spec, verb)
+		args = append(args, arg)
+	}
+	args[0] = string(spec)
+
+	msg := lisp.Call("format", args...).String()
+	switch len(wrapped) {
+	case 0:
+		return &errorString{s: msg}
+	case 1:
+		return &wrapError{msg: msg, err: wrapped[0]}
+	default:
+		return &wrapErrors{msg: msg, errs: wrapped}
+	}
+}
+
+func isFlag(c byte) bool {
+	switch c {
+	case '+', '-', '#', ' ', '.':
+		return true
+	}
+	return c >= '0' && c <= '9'
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+type wrapError struct {
+	msg string
+	err error
+}
+
+func (e *wrapError) Error() string {
+	return e.msg
+}
+
+func (e *wrapError) Unwrap() error {
+	return e.err
+}
+
+type wrapErrors struct {
+	msg  string
+	errs []error
+}
+
+func (e *wrapErrors) Error() string {
+	return e.msg
+}
+
+func (e *wrapErrors) Unwrap() []error {
+	return e.errs
+}
+
+type wrapper interface {
+	Unwrap() error
+}
+
+type multiWrapper interface {
+	Unwrap() []error
+}
+
+type iser interface {
+	Is(error) bool
+}
+
+// unwrap returns errors that are directly wrapped by err.
+func unwrap(err error) []error {
+	switch err := err.(type) {
+	case multiWrapper:
+		return err.Unwrap()
+	case wrapper:
+		if u := err.Unwrap(); u != nil {
+			return []error{u}
+		}
+	}
+	return nil
+}
+
+// Unwrap returns the result of calling the Unwrap method on err,
+// if err's type contains an Unwrap method returning error.
+// Otherwise, Unwrap returns nil.
+//
+// Errors that are created by Errorf with several %w verbs
+// are not unwrapped, just like in Go.
+func Unwrap(err error) error {
+	if u, ok := err.(wrapper); ok {
+		return u.Unwrap()
+	}
+	return nil
+}
+
+// Is reports whether any error in err's tree matches target.
+//
+// The tree consists of err itself, followed by the errors obtained
+// by repeatedly calling Unwrap. An error is considered to match
+// a target if it is equal to that target or if it implements
+// a method Is(error) bool such that Is(target) returns true.
+func Is(err, target error) bool {
+	if err == nil || target == nil {
+		return err == target
+	}
+	return is(err, target)
+}
+
+func is(err, target error) bool {
+	if err == target {
+		return true
+	}
+	if x, ok := err.(iser); ok && x.Is(target) {
+		return true
+	}
+	for _, u := range unwrap(err) {
+		if is(u, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error in err's tree that has type E,
+// and if one is found, sets target to that error value and returns true.
+// Otherwise, it returns false.
+//
+// Unlike Go "errors.As", target type is a type parameter,
+// so it is checked during compilation. "As(any) bool" methods
+// are not consulted.
+func As[E error](err error, target *E) bool {
+	if err == nil {
+		return false
+	}
+	if e, ok := err.(E); ok {
+		*target = e
+		return true
+	}
+	for _, u := range unwrap(err) {
+		if As(u, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorDefined reports whether errorSymbol has defined the error.
+var errorDefined bool
+
+// errorSymbol returns an error symbol that is signaled by Signal.
+// Its error conditions are "(goism-error error)".
+func errorSymbol() lisp.Symbol {
+	sym := lisp.Intern("goism-error")
+	// Error is defined on demand, so package has no initializers.
+	if !errorDefined {
+		lisp.Call("define-error", sym, "Go error")
+		errorDefined = true
+	}
+	return sym
+}
+
+// Message returns Error() result for error value x.
+// It is intended for Emacs Lisp code that receives Go errors:
+//
+//	(goism-errors.Message err)
+//
+// Empty string is returned if x is not a non-nil error.
+func Message(x lisp.Object) string {
+	if err, ok := x.(error); ok {
+		return err.Error()
+	}
+	return ""
+}
+
+// Signal signals "goism-error" condition with err message
+// as error data. Does nothing if err is nil.
+func Signal(err error) {
+	if err != nil {
+		lisp.Call("signal", errorSymbol(), lisp.Call("list", err.Error()))
+	}
+}
+
+// Check reports non-nil err to the caller of exported function.
+// If function is called interactively, err is signaled as "user-error",
+// so Emacs only displays its message; otherwise Check calls Signal.
+// Go functions are not commands, so the command that calls exported
+// function passes the result of "called-interactively-p":
+//
+//	func Rename(from, to string, interactive bool) {
+//		errors.Check(rename(from, to), interactive)
+//	}
+//
+//	(defun my-rename (from to)
+//	  (interactive "fRename: \nFTo: ")
+//	  (goism-pkg.Rename from to (called-interactively-p 'interactive)))
+func Check(err error, interactive bool) {
+	if err == nil {
+		return
+	}
+	if interactive {
+		lisp.Call("user-error", "%s", err.Error())
+	}
+	Signal(err)
+}
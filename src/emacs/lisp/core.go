@@ -4,7 +4,9 @@ package lisp
 // Emacs Lisp function arguments that can be literally "anything".
 // Unlike "interface{}" it does not wrap non-interface types,
 // so Emacs Lisp code can inspect values "as is".
-type any interface{}
+// It is an alias, so "[]interface{}" can be passed as
+// variadic argument: "lisp.Call(fn, args...)".
+type any = interface{}
 
 // Call invokes Emacs Lisp function by its name.
 //
@@ -16,6 +18,14 @@ func Call(fn string, args ...any) Object
 // DynCall is like Call, but permits wider range of callable arguments.
 func DynCall(callable Object, args ...any) Object
 
+// Seq makes Emacs Lisp list or vector usable as "range" expression.
+// Sequence is traversed in place; outside of "range" statement
+// it is copied into a new slice.
+//
+// Example:
+// for i, x := range lisp.Seq(lisp.Call("list", 1, 2)) { ... }
+func Seq(seq Object) []Object
+
 // Object is unboxed Emacs Lisp object.
 // Go-compatible value can be extracted by
 // Object methods.
@@ -0,0 +1,23 @@
+package rt
+
+import (
+	"emacs/lisp"
+)
+
+// IntQuo = "x / y" for integers.
+// Division by zero panics instead of signaling "arith-error".
+func IntQuo(x, y lisp.Object) lisp.Object {
+	if lisp.Call("eq", y, 0).Bool() {
+		panic("integer divide by zero")
+	}
+	return lisp.Call("/", x, y)
+}
+
+// IntRem = "x % y" for integers.
+// Division by zero panics instead of signaling "arith-error".
+func IntRem(x, y lisp.Object) lisp.Object {
+	if lisp.Call("eq", y, 0).Bool() {
+		panic("integer divide by zero")
+	}
+	return lisp.Call("%", x, y)
+}
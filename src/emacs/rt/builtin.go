@@ -5,9 +5,10 @@ import (
 )
 
 // Panic triggers run-time panic.
+// Panic value is signaled with "goism-panic" error symbol.
 //goism:noinline
 func Panic(errorData lisp.Object) {
-	lisp.Call("signal", lisp.Intern("error"), lisp.Call("list", errorData))
+	lisp.Call("signal", panicError, lisp.Call("list", errorData))
 }
 
 // Print prints all arguments;
@@ -0,0 +1,192 @@
+package rt
+
+import (
+	"emacs/lisp"
+)
+
+// Chan - Go channel.
+//
+// Goroutines are Emacs threads, so channel state is guarded
+// by mutex. Blocked operations wait on condition variable
+// that is notified on every channel state change.
+type Chan struct {
+	mutex lisp.Object
+	cond  lisp.Object
+
+	buf  lisp.Object // Ring buffer; has at least 1 element
+	head int         // Index of the first buffered value
+	len  int
+	cap  int
+
+	sent     int // Number of sent values
+	received int // Number of received values
+	closed   bool
+
+	receivers int // Number of blocked receivers, waiting "select" included
+
+	zv lisp.Object // Value that is received from closed channel
+}
+
+// MakeChan creates a new channel with specified buffer capacity.
+// Unbuffered channel still has a single slot for value handoff.
+func MakeChan(capacity int, zv lisp.Object) *Chan {
+	size := capacity
+	if size == 0 {
+		size = 1
+	}
+	mutex := lisp.Call("make-mutex")
+	return &Chan{
+		mutex: mutex,
+		cond:  lisp.Call("make-condition-variable", mutex),
+		buf:   makeVector(size, zv),
+		cap:   capacity,
+		zv:    zv,
+	}
+}
+
+// ChanLen returns number of buffered values.
+func ChanLen(ch *Chan) int {
+	if lisp.Not(ch) || ch.cap == 0 {
+		return 0
+	}
+	return ch.len
+}
+
+// ChanCap returns channel buffer capacity.
+func ChanCap(ch *Chan) int {
+	if lisp.Not(ch) {
+		return 0
+	}
+	return ch.cap
+}
+
+// ChanSend = "ch <- val".
+// For unbuffered channels blocks until value is received.
+func ChanSend(ch *Chan, val lisp.Object) {
+	if lisp.Not(ch) {
+		blockForever()
+	}
+	lisp.Call("mutex-lock", ch.mutex)
+	size := lisp.Length(ch.buf)
+	for !ch.closed && ch.len == size {
+		lisp.Call("condition-wait", ch.cond)
+	}
+	if ch.closed {
+		lisp.Call("mutex-unlock", ch.mutex)
+		panic("send on closed channel")
+	}
+	chanPush(ch, val)
+	chanNotify(ch)
+	seq := ch.sent
+	lisp.Call("mutex-unlock", ch.mutex)
+	selectNotify()
+	if ch.cap == 0 {
+		// Wait until the value is handed off.
+		lisp.Call("mutex-lock", ch.mutex)
+		for !ch.closed && ch.received < seq {
+			lisp.Call("condition-wait", ch.cond)
+		}
+		if ch.received < seq {
+			// Channel is closed before the handoff;
+			// the value is taken back, so it is never received.
+			lisp.Aset(ch.buf, ch.head, ch.zv)
+			ch.len = ch.len - 1
+			ch.sent = ch.sent - 1
+			lisp.Call("mutex-unlock", ch.mutex)
+			panic("send on closed channel")
+		}
+		lisp.Call("mutex-unlock", ch.mutex)
+	}
+}
+
+// ChanRecv = "<-ch".
+// Second result is false if value is received from
+// closed channel that has no buffered values.
+func ChanRecv(ch *Chan) (lisp.Object, bool) {
+	if lisp.Not(ch) {
+		blockForever()
+	}
+	lisp.Call("mutex-lock", ch.mutex)
+	if !ch.closed && ch.len == 0 {
+		// Blocked receiver lets "select" send to unbuffered channel.
+		ch.receivers = ch.receivers + 1
+		lisp.Call("mutex-unlock", ch.mutex)
+		selectNotify()
+		lisp.Call("mutex-lock", ch.mutex)
+		for !ch.closed && ch.len == 0 {
+			lisp.Call("condition-wait", ch.cond)
+		}
+		ch.receivers = ch.receivers - 1
+	}
+	if chanEmpty(ch) {
+		lisp.Call("mutex-unlock", ch.mutex)
+		return ch.zv, false
+	}
+	val := chanPop(ch)
+	chanNotify(ch)
+	lisp.Call("mutex-unlock", ch.mutex)
+	selectNotify()
+	return val, true
+}
+
+// ChanClose = "close(ch)".
+func ChanClose(ch *Chan) {
+	if lisp.Not(ch) {
+		panic("close of nil channel")
+	}
+	lisp.Call("mutex-lock", ch.mutex)
+	if ch.closed {
+		lisp.Call("mutex-unlock", ch.mutex)
+		panic("close of closed channel")
+	}
+	ch.closed = true
+	chanNotify(ch)
+	lisp.Call("mutex-unlock", ch.mutex)
+	selectNotify()
+}
+
+// chanEmpty reports that channel has no values to receive.
+// Value of unbuffered channel that is closed before the handoff
+// is taken back by its sender.
+// Channel mutex must be held by the caller.
+func chanEmpty(ch *Chan) bool {
+	return ch.len == 0 || (ch.cap == 0 && ch.closed)
+}
+
+// chanPush appends value to the buffer.
+// Channel mutex must be held by the caller.
+func chanPush(ch *Chan, val lisp.Object) {
+	size := lisp.Length(ch.buf)
+	lisp.Aset(ch.buf, lisp.Call("%", ch.head+ch.len, size).Int(), val)
+	ch.len = ch.len + 1
+	ch.sent = ch.sent + 1
+}
+
+// chanPop removes the first buffered value.
+// Channel mutex must be held by the caller.
+func chanPop(ch *Chan) lisp.Object {
+	val := aref(ch.buf, ch.head)
+	lisp.Aset(ch.buf, ch.head, ch.zv) // Do not retain received value
+	ch.head = lisp.Call("%", ch.head+1, lisp.Length(ch.buf)).Int()
+	ch.len = ch.len - 1
+	ch.received = ch.received + 1
+	return val
+}
+
+// chanNotify wakes up threads that are blocked on channel operations.
+// Channel mutex must be held by the caller.
+// Pending "select" statements are woken up by selectNotify
+// after the channel mutex is released.
+func chanNotify(ch *Chan) {
+	lisp.Call("condition-notify", ch.cond, true)
+}
+
+// blockForever implements nil channel send and receive.
+func blockForever() {
+	mutex := lisp.Call("make-mutex")
+	cond := lisp.Call("make-condition-variable", mutex)
+	lisp.Call("mutex-lock", mutex)
+	for {
+		lisp.Call("condition-wait", cond)
+	}
+}
@@ -10,7 +10,7 @@ func ToBool(o lisp.Object) bool {
 
 // BytesToStr converts slice of bytes to string.
 func BytesToStr(slice *Slice) string {
-	if slice.offset == 0 {
+	if slice.offset == 0 && slice.len == lisp.Length(slice.data) {
 		return arrayToStr(slice.data)
 	}
 	return arrayToStr(
@@ -22,3 +22,24 @@ func BytesToStr(slice *Slice) string {
 func StrToBytes(s string) *Slice {
 	return ArrayToSlice(strToArray(s))
 }
+
+// RunesToStr converts slice of runes to string.
+func RunesToStr(slice *Slice) string {
+	return BytesToStr(slice)
+}
+
+// StrToRunes converts string to slice of runes.
+// Emacs Lisp strings are sequences of characters,
+// so every element is a decoded rune.
+func StrToRunes(s string) *Slice {
+	return ArrayToSlice(strToArray(s))
+}
+
+// RuneToStr converts rune to its UTF-8 representation.
+// Invalid code points are converted to "�".
+func RuneToStr(r rune) string {
+	if r < 0 || r > 0x10FFFF || (r >= 0xD800 && r <= 0xDFFF) {
+		r = 0xFFFD
+	}
+	return lisp.Call("char-to-string", r).String()
+}
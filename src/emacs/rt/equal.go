@@ -0,0 +1,115 @@
+package rt
+
+import (
+	"emacs/lisp"
+)
+
+// ObjectEq compares dynamic values of interfaces.
+// Numbers and strings are compared by value,
+// other objects are compared by identity.
+// Tagged values are equal if they have identical dynamic
+// types and equal dynamic values.
+func ObjectEq(x, y lisp.Object) bool {
+	if tag := RawIfaceTag(x); !lisp.Not(tag) {
+		return lisp.Eq(tag, RawIfaceTag(y)) &&
+			ObjectEq(lisp.Call("cdr", x), lisp.Call("cdr", y))
+	}
+	if lisp.IsFloat(x) && lisp.IsFloat(y) {
+		return lisp.Call("=", x, y).Bool() // 0.0 == -0.0
+	}
+	if lisp.IsString(x) && lisp.IsString(y) {
+		return lisp.Call("string=", x, y).Bool()
+	}
+	return lisp.Call("eql", x, y).Bool()
+}
+
+// IfaceEq = "x == y" for interface values.
+// Interfaces are equal if they have identical dynamic
+// types and equal dynamic values.
+func IfaceEq(x, y lisp.Object) bool {
+	if lisp.IsSymbol(x) || lisp.IsSymbol(y) {
+		return lisp.Eq(x, y) // At least one of the interfaces is nil
+	}
+	return lisp.Eq(IfaceTag(x), IfaceTag(y)) &&
+		ObjectEq(lisp.Call("cdr", x), lisp.Call("cdr", y))
+}
+
+// objectHash is ObjectEq-compatible hash function.
+func objectHash(x lisp.Object) lisp.Object {
+	if !lisp.Not(RawIfaceTag(x)) {
+		return objectHash(lisp.Call("cdr", x))
+	}
+	if lisp.IsFloat(x) {
+		return floatHash(x)
+	}
+	if lisp.IsString(x) {
+		return lisp.Call("sxhash-equal", x)
+	}
+	return lisp.Call("sxhash-eql", x)
+}
+
+func floatHash(x lisp.Object) lisp.Object {
+	if lisp.Call("=", x, 0).Bool() {
+		return lisp.Call("sxhash-equal", 0.0) // -0.0 and 0.0 have different hashes
+	}
+	return lisp.Call("sxhash-equal", lisp.Call("float", x))
+}
+
+func ifaceHash(x lisp.Object) lisp.Object {
+	if lisp.IsSymbol(x) {
+		return lisp.Call("sxhash-eq", x)
+	}
+	return objectHash(lisp.Call("cdr", x))
+}
+
+func floatEq(x, y lisp.Object) bool {
+	return lisp.Call("=", x, y).Bool()
+}
+
+func ptrHash(p *Ptr) lisp.Object {
+	if lisp.Not(p) {
+		return lisp.Call("sxhash-eq", p)
+	}
+	return lisp.Call("logxor", lisp.Call("sxhash-eq", p.ref), p.index)
+}
+
+// keyHashes maps hash table test names to their hash functions.
+var keyHashes = lisp.Call("make-hash-table", lisp.Intern(":test"), lisp.Intern("eq"))
+
+// Hash table tests for map keys that can not be compared by "equal".
+// Values are test names that are passed to "make-hash-table".
+var (
+	FloatKeyTest  = defineKeyTest("goism-float", floatEq, floatHash)
+	PtrKeyTest    = defineKeyTest("goism-ptr", PtrEq, ptrHash)
+	IfaceKeyTest  = defineKeyTest("goism-iface", IfaceEq, ifaceHash)
+	ObjectKeyTest = defineKeyTest("goism-object", ObjectEq, objectHash)
+)
+
+func defineKeyTest(name string, test, hash interface{}) lisp.Object {
+	sym := lisp.Call("intern", name)
+	lisp.Call("define-hash-table-test", sym, test, hash)
+	lisp.Call("puthash", sym, hash, keyHashes)
+	return sym
+}
+
+// KeyTest returns hash table test for struct and array keys
+// that can not be compared by "equal".
+// Test is defined when it is requested for the first time.
+func KeyTest(name, test, hash lisp.Object) lisp.Object {
+	if lisp.Not(lisp.Call("gethash", name, keyHashes)) {
+		lisp.Call("define-hash-table-test", name, test, hash)
+		lisp.Call("puthash", name, hash, keyHashes)
+	}
+	return name
+}
+
+// KeyHash hashes x by the hash function of the given test.
+func KeyHash(test, x lisp.Object) lisp.Object {
+	if lisp.Eq(test, lisp.Intern("equal")) {
+		return lisp.Call("sxhash-equal", x)
+	}
+	if lisp.Eq(test, lisp.Intern("eq")) {
+		return lisp.Call("sxhash-eq", x)
+	}
+	return lisp.Call("funcall", lisp.Call("gethash", test, keyHashes), x)
+}
@@ -24,6 +24,57 @@ func MakeIface(itab lisp.Object, data lisp.Object) *Iface {
 	return &Iface{itab: itab, data: data}
 }
 
+// itabCache maps (tag . iface) pairs to lazily created itabs.
+var itabCache = lisp.Call("make-hash-table", lisp.Intern(":test"), lisp.Intern("equal"))
+
+// FindItab returns itab that binds type identified by tag
+// to given interface descriptor.
+// Interface descriptor is a vector of interface name
+// followed by (name . signature-hash) pairs of interface methods.
+// Method matches if its "goism-sig" property holds the same hash.
+// Returns nil if type does not implement the interface.
+func FindItab(tag lisp.Object, iface lisp.Object) lisp.Object {
+	key := lisp.Call("cons", tag, iface)
+	entry := lisp.Call("gethash", key, itabCache)
+	if lisp.Not(entry) {
+		entry = lisp.Call("list", makeItab(tag, iface))
+		lisp.Call("puthash", key, entry, itabCache)
+	}
+	return lisp.Call("car", entry)
+}
+
+func makeItab(tag lisp.Object, iface lisp.Object) lisp.Object {
+	itab := lisp.Call("copy-sequence", iface)
+	lisp.Aset(itab, 0, tag)
+	name := lisp.Call("symbol-name", tag).String()
+	if name[0] == '*' {
+		name = name[1:] // Pointer types share methods with their base type
+	}
+	prefix := "goism-" + name + "."
+	n := lisp.Length(iface)
+	for i := 1; i < n; i++ {
+		desc := aref(iface, i)
+		method := lisp.Call("intern", prefix+lisp.Call("car", desc).String())
+		if lisp.Not(lisp.Call("fboundp", method)) {
+			return nilObject
+		}
+		if !lisp.Eq(lisp.Call("get", method, lisp.Intern("goism-sig")), lisp.Call("cdr", desc)) {
+			return nilObject
+		}
+		lisp.Aset(itab, i, method)
+	}
+	return itab
+}
+
+// IfaceTag returns interface value dynamic type tag.
+// Nil interface has no tag; nil is returned for it.
+func IfaceTag(iface lisp.Object) lisp.Object {
+	if lisp.IsSymbol(iface) {
+		return nilObject
+	}
+	return itabTag(lisp.Call("car", iface))
+}
+
 // IfaceCall0 invokes specified function on given interface object.
 //goism:subst
 func IfaceCall0(iface *Iface, fnID int) lisp.Object {
@@ -33,23 +84,35 @@ func IfaceCall0(iface *Iface, fnID int) lisp.Object {
 // IfaceCall1 like IfaceCall0, but for methods with arity=1.
 //goism:subst
 func IfaceCall1(iface *Iface, fnID int, a1 lisp.Object) lisp.Object {
-	return lisp.DynCall(aref(iface.itab, fnID), iface.data, a1)
+	return lisp.DynCall(itabMethod(iface.itab, fnID), iface.data, a1)
 }
 
 // IfaceCall2 like IfaceCall0, but for methods with arity=2.
 //goism:subst
 func IfaceCall2(iface *Iface, fnID int, a1, a2 lisp.Object) lisp.Object {
-	return lisp.DynCall(aref(iface.itab, fnID), iface.data, a1, a2)
+	return lisp.DynCall(itabMethod(iface.itab, fnID), iface.data, a1, a2)
 }
 
 // IfaceCall3 like IfaceCall0, but for methods with arity=3.
 //goism:subst
 func IfaceCall3(iface *Iface, fnID int, a1, a2, a3 lisp.Object) lisp.Object {
-	return lisp.DynCall(aref(iface.itab, fnID), iface.data, a1, a2, a3)
+	return lisp.DynCall(itabMethod(iface.itab, fnID), iface.data, a1, a2, a3)
 }
 
 // IfaceCall4 like IfaceCall0, but for methods with arity=4.
 //goism:subst
 func IfaceCall4(iface *Iface, fnID int, a1, a2, a3, a4 lisp.Object) lisp.Object {
-	return lisp.DynCall(aref(iface.itab, fnID), iface.data, a1, a2, a3, a4)
+	return lisp.DynCall(itabMethod(iface.itab, fnID), iface.data, a1, a2, a3, a4)
+}
+
+// IfaceCallN like IfaceCall0, but for methods with any arity.
+// Arguments are passed as a list.
+func IfaceCallN(iface *Iface, fnID int, args lisp.Object) lisp.Object {
+	return lisp.Call("apply", itabMethod(iface.itab, fnID), iface.data, args)
+}
+
+// IfaceMethodValue returns function that invokes specified
+// method with interface dynamic value bound as a receiver.
+func IfaceMethodValue(iface *Iface, fnID int) lisp.Object {
+	return lisp.Call("apply-partially", itabMethod(iface.itab, fnID), iface.data)
 }
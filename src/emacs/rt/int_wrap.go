@@ -0,0 +1,51 @@
+package rt
+
+import (
+	"emacs/lisp"
+)
+
+// Functions in this file implement exact integer overflow
+// for packages that use "//goism:wraparound" directive.
+// Argument is a result of arithmetic operation that may
+// be out of the destination type range.
+
+// WrapInt8 truncates x to int8 range.
+func WrapInt8(x int) int8 { return int8((x+0x80)&0xFF - 0x80) }
+
+// WrapInt16 truncates x to int16 range.
+func WrapInt16(x int) int16 { return int16((x+0x8000)&0xFFFF - 0x8000) }
+
+// WrapInt32 truncates x to int32 range.
+func WrapInt32(x int) int32 { return int32((x+0x80000000)&0xFFFFFFFF - 0x80000000) }
+
+// WrapUint8 truncates x to uint8 range.
+func WrapUint8(x int) uint8 { return uint8(x & 0xFF) }
+
+// WrapUint16 truncates x to uint16 range.
+func WrapUint16(x int) uint16 { return uint16(x & 0xFFFF) }
+
+// WrapUint32 truncates x to uint32 range.
+func WrapUint32(x int) uint32 { return uint32(x & 0xFFFFFFFF) }
+
+// 64-bit values do not fit into Emacs fixnum,
+// so they are represented by bignums when needed.
+
+// uint64Mask = 2^64-1.
+var uint64Mask = lisp.Call("1-", lisp.Call("ash", 1, 64))
+
+// int64Bias = 2^63.
+var int64Bias = lisp.Call("ash", 1, 63)
+
+// WrapInt64 truncates x to int64 range.
+func WrapInt64(x lisp.Object) lisp.Object {
+	if !lisp.Not(lisp.Call("fixnump", x)) {
+		return x // Fixnums are always inside int64 range
+	}
+	x = lisp.Call("logand", lisp.Call("+", x, int64Bias), uint64Mask)
+	return lisp.Call("-", x, int64Bias)
+}
+
+// WrapUint64 truncates x to uint64 range.
+func WrapUint64(x lisp.Object) lisp.Object {
+	return lisp.Call("logand", x, uint64Mask)
+}
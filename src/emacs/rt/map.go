@@ -4,21 +4,48 @@ import "emacs/lisp"
 
 // MakeMap creates a new map.
 // Default size value is used (which is 65).
-func MakeMap() lisp.Object {
+// Test is a hash table test that implements key type equality.
+func MakeMap(test lisp.Object) lisp.Object {
 	return lisp.Call("make-hash-table",
 		lisp.Intern(":test"),
-		lisp.Intern("equal"))
+		test)
 }
 
 // MakeMapCap creates a new map of specified initial size.
-func MakeMapCap(capacity int) lisp.Object {
+func MakeMapCap(capacity int, test lisp.Object) lisp.Object {
 	return lisp.Call("make-hash-table",
 		lisp.Intern(":test"),
-		lisp.Intern("equal"),
+		test,
 		lisp.Intern(":size"),
 		capacity)
 }
 
+// MapLit creates a new map that is filled with
+// keys and values from the list: (k1 v1 k2 v2 ...).
+func MapLit(test lisp.Object, kvs lisp.Object) lisp.Object {
+	m := MakeMapCap(lisp.Length(kvs)/2, test)
+	for !lisp.Not(kvs) {
+		lisp.Call("puthash", lisp.Call("car", kvs), lisp.Call("cadr", kvs), m)
+		kvs = lisp.Call("cddr", kvs)
+	}
+	return m
+}
+
+// MapMissing is "gethash" default value that can not
+// be stored inside map; uninterned symbol is unique.
+var MapMissing = lisp.Call("make-symbol", "missing")
+
+// MapGetOk = "val, ok := m[key]".
+// Second result is false if map does not contain key;
+// zero value is returned in this case.
+func MapGetOk(m lisp.Object, key lisp.Object, zv lisp.Object) (lisp.Object, bool) {
+	val := lisp.Call("gethash", key, m, MapMissing)
+	if lisp.Eq(val, MapMissing) {
+		return zv, false
+	}
+	return val, true
+}
+
 // MapInsert is a simple wrapper aroung "puthash" which
 // panics if nil map "m" is used.
 func MapInsert(key lisp.Object, val lisp.Object, m lisp.Object) {
@@ -27,3 +54,12 @@ func MapInsert(key lisp.Object, val lisp.Object, m lisp.Object) {
 	}
 	lisp.Call("puthash", key, val, m)
 }
+
+// MapKeys returns a list of map keys.
+func MapKeys(m lisp.Object) lisp.Object {
+	keys := lisp.Call("list")
+	lisp.Call("maphash", func(key, val lisp.Object) {
+		keys = lisp.Call("cons", key, keys)
+	}, m)
+	return keys
+}
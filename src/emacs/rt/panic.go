@@ -0,0 +1,104 @@
+package rt
+
+import (
+	"emacs/lisp"
+)
+
+// panicError is an error symbol that is signaled by Go panics.
+// Its error conditions are "(goism-panic error)".
+var panicError = definePanicError()
+
+// nilObject is Lisp nil typed as lisp.Object.
+var nilObject = lisp.Call("identity", lisp.Intern("nil"))
+
+// nilIface is a nil value of named interface type.
+var nilIface = lisp.Call("identity", lisp.Intern("goism-rt.NilInterface"))
+
+// panicking maps thread to error object that is being
+// handled by its deferred calls. Threads that are not
+// panicking have no entry. Deferred calls may block,
+// so other threads must not observe this state.
+var panicking = lisp.Call(
+	"make-hash-table",
+	lisp.Intern(":test"), lisp.Intern("eq"),
+	lisp.Intern(":weakness"), lisp.Intern("key"),
+)
+
+// hasThreads is false for Emacs that is built without threads.
+var hasThreads = lisp.Call("fboundp", lisp.Intern("current-thread")).Bool()
+
+func currentThread() lisp.Object {
+	if hasThreads {
+		return lisp.Call("current-thread")
+	}
+	return nilObject
+}
+
+func getPanic() lisp.Object {
+	return lisp.Call("gethash", currentThread(), panicking)
+}
+
+func setPanic(err lisp.Object) {
+	if lisp.Not(err) {
+		lisp.Call("remhash", currentThread(), panicking)
+	} else {
+		lisp.Call("puthash", currentThread(), err, panicking)
+	}
+}
+
+func definePanicError() lisp.Symbol {
+	sym := lisp.Intern("goism-panic")
+	lisp.Call("define-error", sym, "Go panic")
+	return sym
+}
+
+// DeferBegin starts deferred calls execution.
+// Err is an error object that interrupted function execution
+// (nil if function is returning normally).
+// Returns panic that was handled before; it is restored by DeferEnd.
+//goism:noinline
+func DeferBegin(err lisp.Object) lisp.Object {
+	prev := getPanic()
+	setPanic(err)
+	return prev
+}
+
+// DeferPanic is called after each deferred call.
+// Err is an error object that was signaled by that call (or nil).
+// New panic replaces the old one; remaining deferred calls
+// are executed anyway.
+//goism:noinline
+func DeferPanic(err lisp.Object) {
+	if !lisp.Not(err) {
+		setPanic(err)
+	}
+}
+
+// DeferEnd finishes deferred calls execution.
+// If deferred calls do not recover, panic is signaled again.
+//goism:noinline
+func DeferEnd(prev lisp.Object) {
+	err := getPanic()
+	setPanic(prev)
+	if !lisp.Not(err) {
+		lisp.Call("signal", lisp.Call("car", err), lisp.Call("cdr", err))
+	}
+}
+
+// Recover stops panicking sequence and returns panic value.
+// For errors that are signaled by Emacs itself,
+// error object is returned as is.
+// Panic state is per thread, so any function that is
+// called during deferred calls execution can recover.
+//goism:noinline
+func Recover() lisp.Object {
+	err := getPanic()
+	if lisp.Not(err) {
+		return err
+	}
+	setPanic(nilObject)
+	if lisp.Eq(lisp.Call("car", err), panicError) {
+		return lisp.Call("car", lisp.Call("cdr", err))
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package rt
+
+import (
+	"emacs/lisp"
+)
+
+// Ptr - pointer to a value that is not a struct or array.
+//
+// Pointers to structs and arrays are represented by objects
+// they point to. Other pointers refer to a slot inside container:
+// vector element (non-negative index), car or cdr of a cons,
+// or a symbol value slot.
+type Ptr struct {
+	ref   lisp.Object
+	index int
+}
+
+// Special index values.
+const (
+	ptrCar    = -1
+	ptrCdr    = -2
+	ptrSymbol = -3
+)
+
+// VectorPtr = "&arr[index]".
+func VectorPtr(vec lisp.Object, index int) *Ptr {
+	return &Ptr{ref: vec, index: index}
+}
+
+// SlicePtr = "&slice[index]".
+func SlicePtr(slice *Slice, index int) *Ptr {
+	return &Ptr{ref: slice.data, index: slice.offset + index}
+}
+
+// CarPtr returns pointer to the cons car.
+// Used for struct fields and boxed variables.
+func CarPtr(cell lisp.Object) *Ptr {
+	return &Ptr{ref: cell, index: ptrCar}
+}
+
+// CdrPtr returns pointer to the cons cdr.
+// Used for the last field of list-represented structs.
+func CdrPtr(cell lisp.Object) *Ptr {
+	return &Ptr{ref: cell, index: ptrCdr}
+}
+
+// SymbolPtr = "&globalVar".
+func SymbolPtr(sym lisp.Object) *Ptr {
+	return &Ptr{ref: sym, index: ptrSymbol}
+}
+
+// PtrLoad = "*p".
+func PtrLoad(p *Ptr) lisp.Object {
+	if lisp.Not(p) {
+		panic("nil pointer dereference")
+	}
+	switch p.index {
+	case ptrCar:
+		return lisp.Call("car", p.ref)
+	case ptrCdr:
+		return lisp.Call("cdr", p.ref)
+	case ptrSymbol:
+		return lisp.Call("symbol-value", p.ref)
+	default:
+		return aref(p.ref, p.index)
+	}
+}
+
+// PtrStore = "*p = val".
+func PtrStore(p *Ptr, val lisp.Object) {
+	if lisp.Not(p) {
+		panic("nil pointer dereference")
+	}
+	switch p.index {
+	case ptrCar:
+		lisp.Call("setcar", p.ref, val)
+	case ptrCdr:
+		lisp.Call("setcdr", p.ref, val)
+	case ptrSymbol:
+		lisp.Call("set", p.ref, val)
+	default:
+		lisp.Aset(p.ref, p.index, val)
+	}
+}
+
+// PtrEq = "p1 == p2".
+// Different pointer objects may refer to the same slot.
+func PtrEq(p1, p2 *Ptr) bool {
+	if p1 == p2 {
+		return true
+	}
+	if p1 == nil || p2 == nil {
+		return false
+	}
+	return lisp.Eq(p1.ref, p2.ref) && p1.index == p2.index
+}
+
+// ArrayAssign = "*p = arr" for array pointers.
+// Array elements are copied into destination vector.
+func ArrayAssign(dst, src lisp.Object) {
+	n := lisp.Length(src)
+	for i := 0; i < n; i++ {
+		lisp.Aset(dst, i, aref(src, i))
+	}
+}
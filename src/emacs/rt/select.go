@@ -0,0 +1,126 @@
+package rt
+
+import (
+	"emacs/lisp"
+)
+
+// All "select" statements wait on the same condition variable.
+// It is notified on every channel state change.
+//
+// Lock order is selectMutex, then channel mutex.
+// Channel operations release channel mutex before
+// they notify select waiters.
+var (
+	selectMutex = lisp.Call("make-mutex")
+	selectCond  = lisp.Call("make-condition-variable", selectMutex)
+)
+
+// selectNotify wakes up pending "select" statements.
+// Channel mutexes must not be held by the caller.
+func selectNotify() {
+	lisp.Call("mutex-lock", selectMutex)
+	lisp.Call("condition-notify", selectCond, true)
+	lisp.Call("mutex-unlock", selectMutex)
+}
+
+// selectWaitRecv adds delta to receivers count of
+// every channel that is used by receive case.
+// Waiting "select" counts as a blocked receiver, so
+// other "select" can send to unbuffered channel.
+func selectWaitRecv(chans []*Chan, vals lisp.Object, delta int) {
+	for i, ch := range chans {
+		if lisp.Not(ch) || !lisp.Not(aref(vals, i)) {
+			continue
+		}
+		lisp.Call("mutex-lock", ch.mutex)
+		ch.receivers = ch.receivers + delta
+		lisp.Call("mutex-unlock", ch.mutex)
+	}
+}
+
+// Select implements "select" statement.
+//
+// Each case is described by channel and vals vector element.
+// Send cases have (val) list as element, receive cases have nil.
+// Ready cases are polled in pseudo-random order.
+// If no case is ready and block is false, -1 is returned;
+// otherwise Select waits until one of the cases can proceed.
+//
+// Returns selected case index.
+// Receive cases also return received value and "ok" flag.
+func Select(chans []*Chan, vals lisp.Object, block bool) (int, lisp.Object, bool) {
+	n := len(chans)
+	lisp.Call("mutex-lock", selectMutex)
+	for {
+		start := 0
+		if n > 1 {
+			start = lisp.Call("random", n).Int()
+		}
+		for i := 0; i < n; i++ {
+			index := lisp.Call("%", start+i, n).Int()
+			ch := chans[index]
+			if lisp.Not(ch) {
+				continue // Nil channel is never ready
+			}
+			send := aref(vals, index)
+			if lisp.Not(send) {
+				val, ok, ready := trySelectRecv(ch)
+				if ready {
+					lisp.Call("mutex-unlock", selectMutex)
+					return index, val, ok
+				}
+			} else if ready, closed := trySelectSend(ch, lisp.Call("car", send)); closed {
+				lisp.Call("mutex-unlock", selectMutex)
+				panic("send on closed channel")
+			} else if ready {
+				lisp.Call("mutex-unlock", selectMutex)
+				return index, nilObject, false
+			}
+		}
+		if !block {
+			lisp.Call("mutex-unlock", selectMutex)
+			return -1, nilObject, false
+		}
+		selectWaitRecv(chans, vals, 1)
+		lisp.Call("condition-wait", selectCond)
+		selectWaitRecv(chans, vals, -1)
+	}
+}
+
+// trySelectRecv receives value if channel has any buffered values
+// or is closed. Third result reports whether receive happened.
+func trySelectRecv(ch *Chan) (lisp.Object, bool, bool) {
+	lisp.Call("mutex-lock", ch.mutex)
+	if chanEmpty(ch) {
+		closed := ch.closed
+		lisp.Call("mutex-unlock", ch.mutex)
+		return ch.zv, false, closed
+	}
+	val := chanPop(ch)
+	chanNotify(ch)
+	lisp.Call("mutex-unlock", ch.mutex)
+	selectNotify()
+	return val, true, true
+}
+
+// trySelectSend sends value if channel has free buffer space.
+// Unbuffered channel accepts value only if there is a blocked receiver.
+// Second result reports that channel is closed; caller must
+// release selectMutex before it panics.
+func trySelectSend(ch *Chan, val lisp.Object) (bool, bool) {
+	lisp.Call("mutex-lock", ch.mutex)
+	if ch.closed {
+		lisp.Call("mutex-unlock", ch.mutex)
+		return false, true
+	}
+	ready := ch.len < ch.cap || (ch.cap == 0 && ch.len == 0 && ch.receivers > 0)
+	if ready {
+		chanPush(ch, val)
+		chanNotify(ch)
+	}
+	lisp.Call("mutex-unlock", ch.mutex)
+	if ready {
+		selectNotify()
+	}
+	return ready, false
+}
@@ -0,0 +1,19 @@
+package rt
+
+import (
+	"emacs/lisp"
+)
+
+// SeqList returns list that has the same elements as seq.
+// Lists are returned as is; other sequences are converted.
+func SeqList(seq lisp.Object) lisp.Object {
+	if lisp.Call("listp", seq).Bool() {
+		return seq
+	}
+	return lisp.Call("append", seq, lisp.Intern("nil"))
+}
+
+// SeqToSlice = "lisp.Seq(seq)".
+func SeqToSlice(seq lisp.Object) *Slice {
+	return ArrayToSlice(lisp.Call("vconcat", seq))
+}
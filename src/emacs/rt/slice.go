@@ -87,6 +87,65 @@ func SlicePush(slice *Slice, val lisp.Object) *Slice {
 	return slice
 }
 
+// SliceAppend = "append(dst, src...)".
+func SliceAppend(dst, src *Slice) *Slice {
+	if src.len == 0 {
+		return dst
+	}
+	length := dst.len + src.len
+	if length > dst.cap {
+		// Need to extend slice storage.
+		// Both slices are copied into a new vector
+		// that has extra space for further appends.
+		newData := lisp.Call(
+			"vconcat",
+			substring(dst.data, dst.offset, dst.offset+dst.len),
+			substring(src.data, src.offset, src.offset+src.len),
+			lisp.Call("make-vector", memExtendPush, lisp.Intern("nil")),
+		)
+		return &Slice{
+			data: newData,
+			len:  length,
+			cap:  length + memExtendPush,
+		}
+	}
+	dstData := dst.data
+	srcData := src.data
+	to := dst.offset + dst.len
+	from := src.offset
+	if lisp.Eq(dstData, srcData) && to > from {
+		// Overlapping regions are copied backwards.
+		for i := src.len - 1; i >= 0; i-- {
+			lisp.Aset(dstData, to+i, aref(srcData, from+i))
+		}
+	} else {
+		for i := 0; i < src.len; i++ {
+			lisp.Aset(dstData, to+i, aref(srcData, from+i))
+		}
+	}
+	return &Slice{
+		data:   dstData,
+		offset: dst.offset,
+		len:    length,
+		cap:    dst.cap,
+	}
+}
+
+// SliceAppendStr = "append(dst, s...)".
+func SliceAppendStr(dst *Slice, s string) *Slice {
+	return SliceAppend(dst, StrToBytes(s))
+}
+
+// SliceList returns list of slice elements.
+// Used to pass slice as variadic argument of Lisp function.
+func SliceList(slice *Slice) lisp.Object {
+	return lisp.Call(
+		"append",
+		substring(slice.data, slice.offset, slice.offset+slice.len),
+		lisp.Intern("nil"),
+	)
+}
+
 func sliceLenBound(slice *Slice, index int) {
 	if index < 0 || index > slice.len {
 		lisp.Error("slice bounds out of range")
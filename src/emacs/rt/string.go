@@ -13,6 +13,15 @@ func StringGet(s string, index int) rune {
 	return MbStringGet(s, index)
 }
 
+// StringRuneWidth returns encoded size of s character
+// that is located at specified (Emacs Lisp) index.
+func StringRuneWidth(s string, index int) int {
+	if !lisp.IsMultibyteString(s) {
+		return 1
+	}
+	return utf8CharWidth(lisp.ArefString(s, index))
+}
+
 // MbStringGet is a part of StringIndex implementation.
 // Called for multibyte strings.
 func MbStringGet(s string, index int) rune {
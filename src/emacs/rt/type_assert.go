@@ -53,3 +53,143 @@ func objectTypeName(x lisp.Object) string {
 	}
 	panic("unexpected type used in lisp.Object type assertion")
 }
+
+// AssertType returns data of interface value if its
+// dynamic type is identified by tag; panics otherwise.
+func AssertType(x lisp.Object, tag lisp.Object) lisp.Object {
+	xtag := IfaceTag(x)
+	if lisp.Eq(xtag, tag) {
+		return lisp.Call("cdr", x)
+	}
+	panic("interface conversion: interface is " + tagName(xtag) + ", not " + tagName(tag))
+}
+
+// AssertTypeOk is like AssertType, but returns zv and
+// false instead of panicking.
+func AssertTypeOk(x lisp.Object, tag lisp.Object, zv lisp.Object) (lisp.Object, bool) {
+	if lisp.Eq(IfaceTag(x), tag) {
+		return lisp.Call("cdr", x), true
+	}
+	return zv, false
+}
+
+// AssertIface converts interface value to another interface;
+// panics if dynamic type does not implement it.
+func AssertIface(x lisp.Object, iface lisp.Object) lisp.Object {
+	tag := IfaceTag(x)
+	if !lisp.Not(tag) {
+		itab := FindItab(tag, iface)
+		if !lisp.Not(itab) {
+			return lisp.Call("cons", itab, lisp.Call("cdr", x))
+		}
+	}
+	panic("interface conversion: " + tagName(tag) + " is not " + aref(iface, 0).String())
+}
+
+// AssertIfaceOk is like AssertIface, but returns nil
+// interface and false instead of panicking.
+func AssertIfaceOk(x lisp.Object, iface lisp.Object) (lisp.Object, bool) {
+	if ImplementsIface(x, iface) {
+		return ConvIface(x, iface), true
+	}
+	return nilIface, false
+}
+
+// ImplementsIface reports whether dynamic type of
+// non-nil interface value implements iface.
+func ImplementsIface(x lisp.Object, iface lisp.Object) bool {
+	tag := IfaceTag(x)
+	return !lisp.Not(tag) && !lisp.Not(FindItab(tag, iface))
+}
+
+// ConvIface converts interface value to another interface
+// which is known to be implemented.
+// Nil interface is returned as is.
+func ConvIface(x lisp.Object, iface lisp.Object) lisp.Object {
+	tag := IfaceTag(x)
+	if lisp.Not(tag) {
+		return x
+	}
+	return lisp.Call("cons", FindItab(tag, iface), lisp.Call("cdr", x))
+}
+
+// AssertRawIface converts raw interface value (lisp.Object or
+// "interface{}") to named interface; panics if x does not hold
+// interface value that implements it.
+func AssertRawIface(x lisp.Object, iface lisp.Object) lisp.Object {
+	if ImplementsRawIface(x, iface) {
+		return ConvIface(x, iface)
+	}
+	panic("interface conversion: interface {} is not " + aref(iface, 0).String())
+}
+
+// AssertRawIfaceOk is like AssertRawIface, but returns nil
+// interface and false instead of panicking.
+func AssertRawIfaceOk(x lisp.Object, iface lisp.Object) (lisp.Object, bool) {
+	if ImplementsRawIface(x, iface) {
+		return ConvIface(x, iface), true
+	}
+	return nilIface, false
+}
+
+// ImplementsRawIface reports whether raw interface value
+// holds interface value that implements iface.
+// Only values that were converted from named interfaces
+// carry their dynamic type; other values never match.
+func ImplementsRawIface(x lisp.Object, iface lisp.Object) bool {
+	tag := RawIfaceTag(x)
+	return !lisp.Not(tag) && !lisp.Not(FindItab(tag, iface))
+}
+
+// RawIfaceTag returns dynamic type tag of raw interface value.
+// Only interface values and values of named types that
+// are not recognized by Emacs Lisp predicates carry the tag;
+// nil is returned for other values.
+func RawIfaceTag(x lisp.Object) lisp.Object {
+	if !lisp.Call("consp", x).Bool() {
+		return nilObject
+	}
+	itab := lisp.Call("car", x)
+	if !lisp.Call("vectorp", itab).Bool() || lisp.Length(itab) == 0 {
+		return nilObject
+	}
+	tag := itabTag(itab)
+	if !lisp.IsSymbol(tag) {
+		return nilObject
+	}
+	return tag
+}
+
+// AssertRawType is like AssertType, but for raw interface values.
+func AssertRawType(x lisp.Object, tag lisp.Object) lisp.Object {
+	xtag := RawIfaceTag(x)
+	if !lisp.Not(xtag) && lisp.Eq(xtag, tag) {
+		return lisp.Call("cdr", x)
+	}
+	panic("interface conversion: interface {} is " + tagName(xtag) + ", not " + tagName(tag))
+}
+
+// AssertRawTypeOk is like AssertRawType, but returns zv and
+// false instead of panicking.
+func AssertRawTypeOk(x lisp.Object, tag lisp.Object, zv lisp.Object) (lisp.Object, bool) {
+	if xtag := RawIfaceTag(x); !lisp.Not(xtag) && lisp.Eq(xtag, tag) {
+		return lisp.Call("cdr", x), true
+	}
+	return zv, false
+}
+
+// AssertPredOk returns x and true if pred returns
+// non-nil for x; otherwise zv and false are returned.
+func AssertPredOk(x lisp.Object, pred lisp.Object, zv lisp.Object) (lisp.Object, bool) {
+	if lisp.Not(lisp.DynCall(pred, x)) {
+		return zv, false
+	}
+	return x, true
+}
+
+func tagName(tag lisp.Object) string {
+	if lisp.Not(tag) {
+		return "nil"
+	}
+	return lisp.Call("symbol-name", tag).String()
+}
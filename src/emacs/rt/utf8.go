@@ -4,11 +4,11 @@ package rt
 // Returned values are in range of [1,4].
 func utf8CharWidth(ch rune) int {
 	switch {
-	case ch <= 127:
+	case ch < 0x80:
 		return 1
-	case ch <= 2048:
+	case ch < 0x800:
 		return 2
-	case ch <= 65536:
+	case ch < 0x10000:
 		return 3
 	default:
 		return 4
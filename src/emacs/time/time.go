@@ -0,0 +1,61 @@
+// Package time provides a subset of Go "time" package
+// that is implemented on top of Emacs timers.
+package time
+
+import (
+	"emacs/lisp"
+)
+
+// Duration is the elapsed time between two instants
+// as an int64 nanosecond count.
+type Duration int64
+
+// Common durations.
+const (
+	Nanosecond  Duration = 1
+	Microsecond          = 1000 * Nanosecond
+	Millisecond          = 1000 * Microsecond
+	Second               = 1000 * Millisecond
+	Minute               = 60 * Second
+	Hour                 = 60 * Minute
+)
+
+// Seconds returns the duration as a floating point number of seconds.
+func (d Duration) Seconds() float64 {
+	return lisp.Call("/", lisp.Call("float", d), 1e9).Float()
+}
+
+// Time is an instant in time with nanosecond precision.
+type Time struct {
+	sec float64 // Result of "float-time"
+}
+
+// Now returns the current time.
+func Now() Time {
+	return Time{sec: lisp.Call("float-time").Float()}
+}
+
+// Unix returns t as a Unix time (number of seconds since 1970).
+func (t Time) Unix() int {
+	return lisp.Call("truncate", t.sec).Int()
+}
+
+// After waits for the duration to elapse and then sends
+// the current time on the returned channel.
+//
+// Channel is fed by "run-at-time" timer, so it is only
+// triggered when Emacs runs timers (for example, while
+// idle or inside "sleep-for").
+func After(d Duration) <-chan Time {
+	ch := make(chan Time, 1)
+	lisp.Call("run-at-time", d.Seconds(), nil, func() {
+		ch <- Now()
+	})
+	return ch
+}
+
+// Sleep pauses the current goroutine for at least the duration d.
+// Timers and other goroutines can run during the pause.
+func Sleep(d Duration) {
+	lisp.Call("sleep-for", d.Seconds())
+}
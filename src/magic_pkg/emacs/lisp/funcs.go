@@ -38,7 +38,10 @@ var (
 	FnHashTableCount = &Func{Name: "hash-table-count"}
 	FnVector         = &Func{Name: "vector"}
 
-	FnStringBytes = &Func{Name: "string-bytes"}
+	FnStringBytes    = &Func{Name: "string-bytes"}
+	FnStringToNumber = &Func{Name: "string-to-number"}
+	FnFloat          = &Func{Name: "float"}
+	FnTruncate       = &Func{Name: "truncate"}
 
 	FnSubstr   = &Func{Name: "substring"}
 	FnConcat   = &Func{Name: "concat"}
@@ -54,9 +57,15 @@ var (
 	FnIsBool   = &Func{Name: "booleanp"}
 	FnList     = &Func{Name: "list"}
 
+	FnApply          = &Func{Name: "apply"}
+	FnApplyPartially = &Func{Name: "apply-partially"}
+	FnMakeThread     = &Func{Name: "make-thread"}
+
 	FnCons   = &Func{Name: "cons"}
 	FnCar    = &Func{Name: "car"}
 	FnCdr    = &Func{Name: "cdr"}
+	FnSetcar = &Func{Name: "setcar"}
+	FnNthcdr = &Func{Name: "nthcdr"}
 	FnAref   = &Func{Name: "aref"}
 	FnAset   = &Func{Name: "aset"}
 	FnMemq   = &Func{Name: "memq"}
@@ -76,6 +85,7 @@ var (
 	FnSub    = &Func{Name: "-"}
 	FnMul    = &Func{Name: "*"}
 	FnQuo    = &Func{Name: "/"}
+	FnRem    = &Func{Name: "%"}
 	FnStrEq  = &Func{Name: "string="}
 	FnStrLt  = &Func{Name: "string<"}
 	FnStrGt  = &Func{Name: "string>"}
@@ -84,6 +94,8 @@ var (
 	FnLogand = &Func{Name: "logand"} // "&"
 	FnLogior = &Func{Name: "logior"} // "|"
 	FnLogxor = &Func{Name: "logxor"} // "^"
+	FnLognot = &Func{Name: "lognot"} // "^" (unary)
+	FnAsh    = &Func{Name: "ash"}    // ">>"
 )
 
 // InternFunc creates lisp function with lispSym name.
@@ -124,6 +136,9 @@ func initFuncs() error {
 			FnHashTableCount,
 			FnVector,
 			FnStringBytes,
+			FnStringToNumber,
+			FnFloat,
+			FnTruncate,
 			FnSubstr,
 			FnConcat,
 			FnNeg,
@@ -137,9 +152,14 @@ func initFuncs() error {
 			FnIsSymbol,
 			FnIsBool,
 			FnList,
+			FnApply,
+			FnApplyPartially,
+			FnMakeThread,
 			FnCons,
 			FnCar,
 			FnCdr,
+			FnSetcar,
+			FnNthcdr,
 			FnAref,
 			FnAset,
 			FnMemq,
@@ -155,6 +175,7 @@ func initFuncs() error {
 			FnSub,
 			FnMul,
 			FnQuo,
+			FnRem,
 			FnStrEq,
 			FnStrLt,
 			FnStrGt,
@@ -163,6 +184,8 @@ func initFuncs() error {
 			FnLogand,
 			FnLogior,
 			FnLogxor,
+			FnLognot,
+			FnAsh,
 		}
 		for _, fn := range funcs {
 			Funcs[fn.Name] = fn
@@ -1,3 +1,32 @@
 package lisp
 
+import (
+	"strings"
+)
+
 type Symbol string
+
+// symbolEscaper escapes characters that are not
+// symbol constituents for Emacs Lisp reader.
+var symbolEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	`'`, `\'`,
+	"`", "\\`",
+	`,`, `\,`,
+	`;`, `\;`,
+	`#`, `\#`,
+	`?`, `\?`,
+	`(`, `\(`,
+	`)`, `\)`,
+	`[`, `\[`,
+	`]`, `\]`,
+	` `, `\ `,
+)
+
+// Repr returns printed representation of the symbol that is
+// read back as the same symbol.
+// Generic instance names like "f[int,string]" need escaping.
+func (sym Symbol) Repr() string {
+	return symbolEscaper.Replace(string(sym))
+}
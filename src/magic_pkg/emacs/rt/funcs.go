@@ -10,16 +10,45 @@ import (
 var FnIfaceCall [5]*sexp.Func
 
 var (
-	FnMakeIface *sexp.Func
-
-	FnPanic   *sexp.Func
-	FnPrint   *sexp.Func
-	FnPrintln *sexp.Func
+	FnMakeIface        *sexp.Func
+	FnIfaceEq          *sexp.Func
+	FnObjectEq         *sexp.Func
+	FnKeyTest          *sexp.Func
+	FnKeyHash          *sexp.Func
+	FnIfaceTag         *sexp.Func
+	FnIfaceMethodValue *sexp.Func
+	FnIfaceCallN       *sexp.Func // Calls with more than 4 arguments
+
+	FnAssertType      *sexp.Func
+	FnAssertTypeOk    *sexp.Func
+	FnAssertIface     *sexp.Func
+	FnAssertIfaceOk   *sexp.Func
+	FnAssertPredOk    *sexp.Func
+	FnImplementsIface *sexp.Func
+	FnConvIface       *sexp.Func
+
+	FnAssertRawIface     *sexp.Func
+	FnAssertRawIfaceOk   *sexp.Func
+	FnImplementsRawIface *sexp.Func
+	FnAssertRawType      *sexp.Func
+	FnAssertRawTypeOk    *sexp.Func
+	FnRawIfaceTag        *sexp.Func
+
+	FnPanic      *sexp.Func
+	FnRecover    *sexp.Func
+	FnDeferBegin *sexp.Func
+	FnDeferPanic *sexp.Func
+	FnDeferEnd   *sexp.Func
+	FnPrint      *sexp.Func
+	FnPrintln    *sexp.Func
 
 	FnMakeSlice      *sexp.Func
 	FnMakeSliceCap   *sexp.Func
 	FnSliceCopy      *sexp.Func
 	FnSlicePush      *sexp.Func
+	FnSliceAppend    *sexp.Func
+	FnSliceAppendStr *sexp.Func
+	FnSliceList      *sexp.Func
 	FnSliceLen       *sexp.Func
 	FnSliceCap       *sexp.Func
 	FnSliceGet       *sexp.Func
@@ -32,20 +61,60 @@ var (
 	FnArraySliceLow  *sexp.Func
 	FnArraySliceHigh *sexp.Func
 
-	FnStringGet *sexp.Func
+	FnStringGet       *sexp.Func
+	FnStringRuneWidth *sexp.Func
+
+	FnSeqList    *sexp.Func
+	FnSeqToSlice *sexp.Func
 
 	FnBytesToStr *sexp.Func
 	FnStrToBytes *sexp.Func
+	FnRunesToStr *sexp.Func
+	FnStrToRunes *sexp.Func
+	FnRuneToStr  *sexp.Func
+
+	FnMakeChan  *sexp.Func
+	FnChanLen   *sexp.Func
+	FnChanCap   *sexp.Func
+	FnChanSend  *sexp.Func
+	FnChanRecv  *sexp.Func
+	FnChanClose *sexp.Func
+	FnSelect    *sexp.Func
 
 	FnMakeMap    *sexp.Func
 	FnMakeMapCap *sexp.Func
+	FnMapLit     *sexp.Func
+	FnMapGetOk   *sexp.Func
 	FnMapInsert  *sexp.Func
+	FnMapKeys    *sexp.Func
 
 	FnCoerceBool   *sexp.Func
 	FnCoerceInt    *sexp.Func
 	FnCoerceFloat  *sexp.Func
 	FnCoerceString *sexp.Func
 	FnCoerceSymbol *sexp.Func
+
+	FnWrapInt8   *sexp.Func
+	FnWrapInt16  *sexp.Func
+	FnWrapInt32  *sexp.Func
+	FnWrapInt64  *sexp.Func
+	FnWrapUint8  *sexp.Func
+	FnWrapUint16 *sexp.Func
+	FnWrapUint32 *sexp.Func
+	FnWrapUint64 *sexp.Func
+
+	FnIntQuo *sexp.Func
+	FnIntRem *sexp.Func
+
+	FnVectorPtr   *sexp.Func
+	FnSlicePtr    *sexp.Func
+	FnCarPtr      *sexp.Func
+	FnCdrPtr      *sexp.Func
+	FnSymbolPtr   *sexp.Func
+	FnPtrLoad     *sexp.Func
+	FnPtrStore    *sexp.Func
+	FnPtrEq       *sexp.Func
+	FnArrayAssign *sexp.Func
 )
 
 func InitFuncs(ftab *symbols.FuncTable) {
@@ -59,10 +128,35 @@ func InitFuncs(ftab *symbols.FuncTable) {
 	for i := range FnIfaceCall {
 		FnIfaceCall[i] = mustFindFunc(fmt.Sprintf("IfaceCall%d", i))
 	}
+	FnIfaceCallN = mustFindFunc("IfaceCallN")
 
 	FnMakeIface = mustFindFunc("MakeIface")
+	FnIfaceEq = mustFindFunc("IfaceEq")
+	FnObjectEq = mustFindFunc("ObjectEq")
+	FnKeyTest = mustFindFunc("KeyTest")
+	FnKeyHash = mustFindFunc("KeyHash")
+	FnIfaceTag = mustFindFunc("IfaceTag")
+	FnIfaceMethodValue = mustFindFunc("IfaceMethodValue")
+
+	FnAssertType = mustFindFunc("AssertType")
+	FnAssertTypeOk = mustFindFunc("AssertTypeOk")
+	FnAssertIface = mustFindFunc("AssertIface")
+	FnAssertIfaceOk = mustFindFunc("AssertIfaceOk")
+	FnAssertPredOk = mustFindFunc("AssertPredOk")
+	FnImplementsIface = mustFindFunc("ImplementsIface")
+	FnAssertRawIface = mustFindFunc("AssertRawIface")
+	FnAssertRawIfaceOk = mustFindFunc("AssertRawIfaceOk")
+	FnImplementsRawIface = mustFindFunc("ImplementsRawIface")
+	FnAssertRawType = mustFindFunc("AssertRawType")
+	FnAssertRawTypeOk = mustFindFunc("AssertRawTypeOk")
+	FnRawIfaceTag = mustFindFunc("RawIfaceTag")
+	FnConvIface = mustFindFunc("ConvIface")
 
 	FnPanic = mustFindFunc("Panic")
+	FnRecover = mustFindFunc("Recover")
+	FnDeferBegin = mustFindFunc("DeferBegin")
+	FnDeferPanic = mustFindFunc("DeferPanic")
+	FnDeferEnd = mustFindFunc("DeferEnd")
 	FnPrint = mustFindFunc("Print")
 	FnPrintln = mustFindFunc("Println")
 
@@ -70,6 +164,9 @@ func InitFuncs(ftab *symbols.FuncTable) {
 	FnMakeSliceCap = mustFindFunc("MakeSliceCap")
 	FnSliceCopy = mustFindFunc("SliceCopy")
 	FnSlicePush = mustFindFunc("SlicePush")
+	FnSliceAppend = mustFindFunc("SliceAppend")
+	FnSliceAppendStr = mustFindFunc("SliceAppendStr")
+	FnSliceList = mustFindFunc("SliceList")
 	FnSliceLen = mustFindFunc("SliceLen")
 	FnSliceCap = mustFindFunc("SliceCap")
 	FnSliceGet = mustFindFunc("SliceGet")
@@ -83,17 +180,57 @@ func InitFuncs(ftab *symbols.FuncTable) {
 	FnArraySliceHigh = mustFindFunc("ArraySliceHigh")
 
 	FnStringGet = mustFindFunc("StringGet")
+	FnStringRuneWidth = mustFindFunc("StringRuneWidth")
+
+	FnSeqList = mustFindFunc("SeqList")
+	FnSeqToSlice = mustFindFunc("SeqToSlice")
 
 	FnBytesToStr = mustFindFunc("BytesToStr")
 	FnStrToBytes = mustFindFunc("StrToBytes")
+	FnRunesToStr = mustFindFunc("RunesToStr")
+	FnStrToRunes = mustFindFunc("StrToRunes")
+	FnRuneToStr = mustFindFunc("RuneToStr")
+
+	FnMakeChan = mustFindFunc("MakeChan")
+	FnChanLen = mustFindFunc("ChanLen")
+	FnChanCap = mustFindFunc("ChanCap")
+	FnChanSend = mustFindFunc("ChanSend")
+	FnChanRecv = mustFindFunc("ChanRecv")
+	FnChanClose = mustFindFunc("ChanClose")
+	FnSelect = mustFindFunc("Select")
 
 	FnMakeMap = mustFindFunc("MakeMap")
 	FnMakeMapCap = mustFindFunc("MakeMapCap")
+	FnMapLit = mustFindFunc("MapLit")
+	FnMapGetOk = mustFindFunc("MapGetOk")
 	FnMapInsert = mustFindFunc("MapInsert")
+	FnMapKeys = mustFindFunc("MapKeys")
 
 	FnCoerceBool = mustFindFunc("CoerceBool")
 	FnCoerceInt = mustFindFunc("CoerceInt")
 	FnCoerceFloat = mustFindFunc("CoerceFloat")
 	FnCoerceString = mustFindFunc("CoerceString")
 	FnCoerceSymbol = mustFindFunc("CoerceSymbol")
+
+	FnWrapInt8 = mustFindFunc("WrapInt8")
+	FnWrapInt16 = mustFindFunc("WrapInt16")
+	FnWrapInt32 = mustFindFunc("WrapInt32")
+	FnWrapInt64 = mustFindFunc("WrapInt64")
+	FnWrapUint8 = mustFindFunc("WrapUint8")
+	FnWrapUint16 = mustFindFunc("WrapUint16")
+	FnWrapUint32 = mustFindFunc("WrapUint32")
+	FnWrapUint64 = mustFindFunc("WrapUint64")
+
+	FnIntQuo = mustFindFunc("IntQuo")
+	FnIntRem = mustFindFunc("IntRem")
+
+	FnVectorPtr = mustFindFunc("VectorPtr")
+	FnSlicePtr = mustFindFunc("SlicePtr")
+	FnCarPtr = mustFindFunc("CarPtr")
+	FnCdrPtr = mustFindFunc("CdrPtr")
+	FnSymbolPtr = mustFindFunc("SymbolPtr")
+	FnPtrLoad = mustFindFunc("PtrLoad")
+	FnPtrStore = mustFindFunc("PtrStore")
+	FnPtrEq = mustFindFunc("PtrEq")
+	FnArrayAssign = mustFindFunc("ArrayAssign")
 }
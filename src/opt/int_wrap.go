@@ -0,0 +1,149 @@
+package opt
+
+import (
+	"go/types"
+	"magic_pkg/emacs/lisp"
+	"magic_pkg/emacs/rt"
+	"sexp"
+)
+
+// ElideWraps removes integer truncation calls (see "//goism:wraparound")
+// when their argument is known to be inside destination type range.
+func ElideWraps(fn *sexp.Func) bool {
+	triggered := false
+	fn.Body = sexp.Rewrite(fn.Body, func(form sexp.Form) sexp.Form {
+		cast, ok := form.(*sexp.TypeCast)
+		if !ok {
+			return nil
+		}
+		call, ok := cast.Form.(*sexp.Call)
+		if !ok || !isWrapFunc(call.Fn) {
+			return nil
+		}
+		r, ok := rangeOf(cast.Typ)
+		if ok && r.fits(call.Args[0]) {
+			triggered = true
+			return &sexp.TypeCast{Form: call.Args[0], Typ: cast.Typ}
+		}
+		return nil
+	}).(sexp.Block)
+	return triggered
+}
+
+func isWrapFunc(fn *sexp.Func) bool {
+	switch fn {
+	case rt.FnWrapInt8, rt.FnWrapInt16, rt.FnWrapInt32, rt.FnWrapInt64:
+		return true
+	case rt.FnWrapUint8, rt.FnWrapUint16, rt.FnWrapUint32, rt.FnWrapUint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// intRange describes fixed-width integer type values range.
+type intRange struct {
+	bits   uint
+	signed bool
+}
+
+func rangeOf(typ types.Type) (intRange, bool) {
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		return intRange{}, false
+	}
+	switch basic.Kind() {
+	case types.Int8:
+		return intRange{bits: 8, signed: true}, true
+	case types.Int16:
+		return intRange{bits: 16, signed: true}, true
+	case types.Int32:
+		return intRange{bits: 32, signed: true}, true
+	case types.Int64:
+		return intRange{bits: 64, signed: true}, true
+	case types.Uint8:
+		return intRange{bits: 8}, true
+	case types.Uint16:
+		return intRange{bits: 16}, true
+	case types.Uint32:
+		return intRange{bits: 32}, true
+	case types.Uint64, types.Uint, types.Uintptr:
+		return intRange{bits: 64}, true
+
+	default:
+		// "int" values are not truncated, so
+		// their range is unknown.
+		return intRange{}, false
+	}
+}
+
+func (r intRange) contains(other intRange) bool {
+	if r.signed == other.signed {
+		return other.bits <= r.bits
+	}
+	return r.signed && other.bits < r.bits
+}
+
+func (r intRange) holds(x int64) bool {
+	if r.signed {
+		return r.bits == 64 || (x >= -1<<(r.bits-1) && x < 1<<(r.bits-1))
+	}
+	return x >= 0 && (r.bits == 64 || x < 1<<r.bits)
+}
+
+func (r intRange) holdsType(typ types.Type) bool {
+	other, ok := rangeOf(typ)
+	return ok && r.contains(other)
+}
+
+// fits reports whether form value is provably inside r.
+// Values of fixed-width integer types are assumed to be
+// inside their type range unless they are produced by
+// arithmetic operations or conversions.
+func (r intRange) fits(form sexp.Form) bool {
+	switch form := form.(type) {
+	case sexp.Int:
+		return r.holds(int64(form))
+
+	case *sexp.TypeCast:
+		if call, ok := form.Form.(*sexp.Call); ok && isWrapFunc(call.Fn) {
+			return r.holdsType(form.Typ)
+		}
+		return r.fits(form.Form)
+
+	case *sexp.LispCall:
+		switch form.Fn {
+		case lisp.FnLogand:
+			// Masking by small non-negative constant
+			// gives value inside [0, mask] range.
+			for _, arg := range form.Args {
+				if x, ok := arg.(sexp.Int); ok && x >= 0 && r.holds(int64(x)) {
+					return true
+				}
+			}
+			return r.fitsAll(form.Args)
+		case lisp.FnLogior, lisp.FnLogxor, lisp.FnRem:
+			return r.fitsAll(form.Args)
+		case lisp.FnLognot:
+			return r.signed && r.fits(form.Args[0])
+		case lisp.FnAsh:
+			// Right shift never increases magnitude.
+			if shift, ok := form.Args[1].(*sexp.LispCall); ok && shift.Fn == lisp.FnNeg {
+				return r.fits(form.Args[0])
+			}
+		}
+		return false
+
+	default:
+		return r.holdsType(form.Type())
+	}
+}
+
+func (r intRange) fitsAll(forms []sexp.Form) bool {
+	for _, form := range forms {
+		if !r.fits(form) {
+			return false
+		}
+	}
+	return true
+}
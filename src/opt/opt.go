@@ -21,7 +21,8 @@ func runOptPass(funcs []*sexp.Func) bool {
 }
 
 func optimizeFunc(fn *sexp.Func) bool {
-	return InlineCalls(fn) ||
+	return ElideWraps(fn) ||
+		InlineCalls(fn) ||
 		FoldConstexpr(fn) ||
 		ReduceStrength(fn)
 }
@@ -152,7 +152,14 @@ func (sr *strengthReducer) weakenBytesToStr(form *sexp.Call) sexp.Form {
 		case sexp.SpanWhole:
 			return sexp.NewConcat(arg.Array, sexp.Str(""))
 		default:
-			sub := sexp.NewSubstr(arg.Array, arg.Low, arg.High)
+			low, high := arg.Low, arg.High
+			if low == nil {
+				low = sexp.Nil
+			}
+			if high == nil {
+				high = sexp.Nil
+			}
+			sub := sexp.NewSubstr(arg.Array, low, high)
 			return sexp.NewConcat(sub, sexp.Str(""))
 		}
 	}
@@ -83,6 +83,8 @@ func width(form sexp.Form) int {
 		return 2
 	case *sexp.Label:
 		return 0
+	case *sexp.Protect:
+		return width(form.Body) + 5
 	case *sexp.Repeat:
 		return -1 // #REFS: 90
 	case *sexp.DoTimes:
@@ -121,6 +123,11 @@ func width(form sexp.Form) int {
 		return widthOfList(form.Body) + widthOfBindList(form.Args) + 2
 	case *sexp.DynCall:
 		return width(form.Callable) + widthOfList(form.Args) + 2
+	case *sexp.Lambda:
+		if len(form.Captured) == 0 {
+			return 1
+		}
+		return widthOfList(form.Captured) + 3
 
 	case *sexp.Let:
 		if form.Expr != nil {
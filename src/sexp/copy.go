@@ -91,6 +91,9 @@ func (form *ExprStmt) Copy() Form {
 }
 func (form *Goto) Copy() Form  { return &Goto{LabelName: form.LabelName} }
 func (form *Label) Copy() Form { return &Label{Name: form.Name} }
+func (form *Protect) Copy() Form {
+	return &Protect{Body: form.Body.Copy().(Block), ErrVar: form.ErrVar}
+}
 
 func (form *Repeat) Copy() Form {
 	return &Repeat{
@@ -180,6 +183,13 @@ func (call *DynCall) Copy() Form {
 		Typ:      call.Typ,
 	}
 }
+func (form *Lambda) Copy() Form {
+	return &Lambda{
+		Fn:       form.Fn,
+		Captured: CopyList(form.Captured),
+		Typ:      form.Typ,
+	}
+}
 
 func (form *Let) Copy() Form {
 	binds := copyBindList(form.Bindings)
@@ -213,10 +223,14 @@ func CopyList(forms []Form) []Form {
 }
 
 func copySpan(span Span) Span {
-	return Span{
-		Low:  span.Low.Copy(),
-		High: span.High.Copy(),
+	var res Span
+	if span.Low != nil {
+		res.Low = span.Low.Copy()
 	}
+	if span.High != nil {
+		res.High = span.High.Copy()
+	}
+	return res
 }
 
 func copySwitchBody(b SwitchBody) SwitchBody {
@@ -134,6 +134,9 @@ func (form *ExprStmt) Cost() int {
 }
 func (form *Goto) Cost() int  { return 1 }
 func (form *Label) Cost() int { return 0 }
+func (form *Protect) Cost() int {
+	return form.Body.Cost() + 5
+}
 
 func (form *Repeat) Cost() int {
 	return form.Body.Cost() * int(form.N)
@@ -192,6 +195,12 @@ func (call *LambdaCall) Cost() int {
 func (call *DynCall) Cost() int {
 	return call.Callable.Cost() + costOfCall(call.Args)
 }
+func (form *Lambda) Cost() int {
+	if len(form.Captured) == 0 {
+		return 1
+	}
+	return costOfCall(form.Captured) + 1
+}
 
 func (form *Let) Cost() int {
 	return form.Expr.Cost() + costOfBindList(form.Bindings)
@@ -159,6 +159,14 @@ type (
 
 	// Label = "Name:".
 	Label struct{ Name string }
+
+	// Protect executes Body with installed error handler.
+	// If error is signaled, error object is assigned to
+	// ErrVar local and execution continues after Protect.
+	Protect struct {
+		Body   Block
+		ErrVar string
+	}
 )
 
 // Loop forms.
@@ -271,6 +279,17 @@ type DynCall struct {
 	Typ      types.Type
 }
 
+// Lambda is a function value (closure).
+// Captured forms are bound to the first Fn parameters
+// when lambda is created; remaining parameters are
+// supplied by the caller.
+// Lambda without captured forms is a plain function reference.
+type Lambda struct {
+	Fn       *Func
+	Captured []Form
+	Typ      *types.Signature
+}
+
 // Let introduces bindings that are visible to a
 // statement or expression. Bindings are destroyed after
 // wrapped form is evaluated.
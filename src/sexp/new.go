@@ -40,14 +40,17 @@ func NewAdd1(x Form) *LispCall { return NewLispCall(lisp.FnAdd1, x) }
 func NewSub1(x Form) *LispCall { return NewLispCall(lisp.FnSub1, x) }
 
 func NewShl(x, y Form) *LispCall    { return NewLispCall(lisp.FnLsh, x, y) }
-func NewShr(x, y Form) *LispCall    { return NewLispCall(lisp.FnLsh, x, NewNeg(y)) }
+func NewShr(x, y Form) *LispCall    { return NewLispCall(lisp.FnAsh, x, NewNeg(y)) }
 func NewBitOr(x, y Form) *LispCall  { return NewLispCall(lisp.FnLogior, x, y) }
 func NewBitAnd(x, y Form) *LispCall { return NewLispCall(lisp.FnLogand, x, y) }
 func NewBitXor(x, y Form) *LispCall { return NewLispCall(lisp.FnLogxor, x, y) }
+func NewBitNot(x Form) *LispCall    { return NewLispCall(lisp.FnLognot, x) }
+func NewAndNot(x, y Form) *LispCall { return NewBitAnd(x, NewBitNot(y)) }
 func NewAdd(x, y Form) *LispCall    { return NewLispCall(lisp.FnAdd, x, y) }
 func NewSub(x, y Form) *LispCall    { return NewLispCall(lisp.FnSub, x, y) }
 func NewMul(x, y Form) *LispCall    { return NewLispCall(lisp.FnMul, x, y) }
 func NewQuo(x, y Form) *LispCall    { return NewLispCall(lisp.FnQuo, x, y) }
+func NewRem(x, y Form) *LispCall    { return NewLispCall(lisp.FnRem, x, y) }
 func NewNumEq(x, y Form) *LispCall  { return NewLispCall(lisp.FnNumEq, x, y) }
 func NewNumNeq(x, y Form) *LispCall { return NewNot(NewNumEq(x, y)) }
 func NewNumLt(x, y Form) *LispCall  { return NewLispCall(lisp.FnNumLt, x, y) }
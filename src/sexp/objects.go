@@ -43,9 +43,12 @@ func (fn *Func) LoadDirective(directive string) {
 
 // Func represents goism function object.
 type Func struct {
-	Name     string
-	Body     Block
-	Params   []string
+	Name   string
+	Body   Block
+	Params []string
+	// True if the last parameter is "&rest" list.
+	// Only exported entry points of variadic functions
+	// take "&rest"; Go callers pass a slice.
 	Variadic bool
 
 	// Maps param index to interface type.
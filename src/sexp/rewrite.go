@@ -33,6 +33,12 @@ func Rewrite(form Form, fn rewriteFunc) Form {
 	case *Label:
 		return rewriteAtom(form, fn)
 
+	case *Protect:
+		if form := fn(form); form != nil {
+			return form
+		}
+		form.Body = Rewrite(form.Body, fn).(Block)
+
 	case *SparseArrayLit:
 		if form := fn(form); form != nil {
 			return form
@@ -105,12 +111,15 @@ func Rewrite(form Form, fn rewriteFunc) Form {
 		if form := fn(form); form != nil {
 			return form
 		}
+		form.Init = Rewrite(form.Init, fn)
+		form.Post = Rewrite(form.Post, fn)
 		form.Body = Rewrite(form.Body, fn).(Block)
 
 	case *While:
 		if form := fn(form); form != nil {
 			return form
 		}
+		form.Init = Rewrite(form.Init, fn)
 		form.Cond = Rewrite(form.Cond, fn)
 		form.Post = Rewrite(form.Post, fn)
 		form.Body = Rewrite(form.Body, fn).(Block)
@@ -135,6 +144,8 @@ func Rewrite(form Form, fn rewriteFunc) Form {
 		for i, arg := range form.Args {
 			form.Args[i] = Rewrite(arg, fn)
 		}
+	case *Lambda:
+		return rewriteList(form, form.Captured, fn)
 
 	case *Let:
 		if form := fn(form); form != nil {
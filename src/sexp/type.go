@@ -34,6 +34,7 @@ func (form *Return) Type() types.Type       { return xtypes.TypVoid }
 func (form *ExprStmt) Type() types.Type     { return xtypes.TypVoid }
 func (form *Goto) Type() types.Type         { return xtypes.TypVoid }
 func (form *Label) Type() types.Type        { return xtypes.TypVoid }
+func (form *Protect) Type() types.Type      { return xtypes.TypVoid }
 
 func (form *Repeat) Type() types.Type  { return xtypes.TypVoid }
 func (form *DoTimes) Type() types.Type { return xtypes.TypVoid }
@@ -41,7 +42,8 @@ func (form *Loop) Type() types.Type    { return xtypes.TypVoid }
 func (form *While) Type() types.Type   { return xtypes.TypVoid }
 
 func (form *ArrayIndex) Type() types.Type {
-	return form.Array.Type().Underlying().(*types.Array).Elem()
+	// Pointer to array is the array itself.
+	return xtypes.MaybeDeref(form.Array.Type()).Underlying().(*types.Array).Elem()
 }
 func (form *SliceIndex) Type() types.Type {
 	return form.Slice.Type().Underlying().(*types.Slice).Elem()
@@ -64,7 +66,13 @@ func (call *Call) Type() types.Type {
 }
 func (call *LispCall) Type() types.Type {
 	switch call.Fn {
-	case lisp.FnSub, lisp.FnAdd, lisp.FnMul, lisp.FnQuo, lisp.FnMin:
+	case lisp.FnSub, lisp.FnAdd, lisp.FnMul, lisp.FnQuo, lisp.FnRem, lisp.FnMin:
+		return call.Args[0].Type()
+	case lisp.FnNeg, lisp.FnAdd1, lisp.FnSub1:
+		return call.Args[0].Type()
+	case lisp.FnLogand, lisp.FnLogior, lisp.FnLogxor, lisp.FnLognot:
+		return call.Args[0].Type()
+	case lisp.FnLsh, lisp.FnAsh:
 		return call.Args[0].Type()
 
 	case lisp.FnConcat:
@@ -88,6 +96,7 @@ func (call *LispCall) Type() types.Type {
 }
 func (call *LambdaCall) Type() types.Type { return call.Typ }
 func (call *DynCall) Type() types.Type    { return call.Typ }
+func (form *Lambda) Type() types.Type     { return form.Typ }
 
 func (form *Let) Type() types.Type {
 	if form.Expr == nil {
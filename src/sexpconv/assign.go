@@ -5,6 +5,7 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"magic_pkg/emacs/lisp"
 	"magic_pkg/emacs/rt"
 	"sexp"
 	"xtypes"
@@ -22,22 +23,35 @@ func isBlankIdent(node ast.Expr) bool {
 
 func (conv *converter) AssignStmt(node *ast.AssignStmt) sexp.Form {
 	switch node.Tok {
+	case token.ASSIGN, token.DEFINE:
+		return conv.genAssign(node.Lhs, node.Rhs)
 	case token.ADD_ASSIGN:
 		return conv.addAssign(node.Lhs[0], node.Rhs[0])
-	case token.SUB_ASSIGN:
-		return conv.subAssign(node.Lhs[0], node.Rhs[0])
-	case token.MUL_ASSIGN:
-		return conv.mulAssign(node.Lhs[0], node.Rhs[0])
-	case token.QUO_ASSIGN:
-		return conv.quoAssign(node.Lhs[0], node.Rhs[0])
 
 	default:
-		return conv.genAssign(node.Lhs, node.Rhs)
+		return conv.opAssign(node)
 	}
 }
 
+// Compound assignment tokens mapped to their binary operators.
+var assignOps = map[token.Token]token.Token{
+	token.SUB_ASSIGN:     token.SUB,
+	token.MUL_ASSIGN:     token.MUL,
+	token.QUO_ASSIGN:     token.QUO,
+	token.REM_ASSIGN:     token.REM,
+	token.AND_ASSIGN:     token.AND,
+	token.OR_ASSIGN:      token.OR,
+	token.XOR_ASSIGN:     token.XOR,
+	token.SHL_ASSIGN:     token.SHL,
+	token.SHR_ASSIGN:     token.SHR,
+	token.AND_NOT_ASSIGN: token.AND_NOT,
+}
+
 func (conv *converter) genAssign(lhs, rhs []ast.Expr) sexp.Form {
 	if len(lhs) == len(rhs) {
+		if len(lhs) > 1 {
+			return conv.parallelAssign(lhs, rhs)
+		}
 		return conv.singleValueAssign(lhs, rhs)
 	}
 	return conv.multiValueAssign(lhs, rhs[0])
@@ -45,54 +59,91 @@ func (conv *converter) genAssign(lhs, rhs []ast.Expr) sexp.Form {
 
 func (conv *converter) addAssign(lhs ast.Expr, rhs ast.Expr) sexp.Form {
 	typ := conv.basicTypeOf(rhs)
-	x, y := conv.Expr(lhs), conv.Expr(rhs)
-	if typ.Kind() == types.String {
-		return conv.assign(lhs, sexp.NewConcat(x, y))
-	}
-	return conv.assign(lhs, sexp.NewAdd(x, y))
+	return conv.update(lhs, func(x sexp.Form) sexp.Form {
+		y := conv.Expr(rhs)
+		if typ.Kind() == types.String {
+			return sexp.NewConcat(x, y)
+		}
+		return conv.arith(token.ADD, conv.typeOf(lhs), x, y)
+	})
 }
 
-func (conv *converter) subAssign(lhs ast.Expr, rhs ast.Expr) sexp.Form {
-	x, y := conv.Expr(lhs), conv.Expr(rhs)
-	return conv.assign(lhs, sexp.NewSub(x, y))
+// opAssign = "x op= y".
+func (conv *converter) opAssign(node *ast.AssignStmt) sexp.Form {
+	lhs, rhs := node.Lhs[0], node.Rhs[0]
+	return conv.update(lhs, func(x sexp.Form) sexp.Form {
+		form := conv.arith(assignOps[node.Tok], conv.typeOf(lhs), x, conv.Expr(rhs))
+		if form == nil {
+			panic(errUnexpectedStmt(conv, node))
+		}
+		return form
+	})
 }
 
-func (conv *converter) mulAssign(lhs ast.Expr, rhs ast.Expr) sexp.Form {
-	x, y := conv.Expr(lhs), conv.Expr(rhs)
-	return conv.assign(lhs, sexp.NewMul(x, y))
-}
+// Local that holds packed results of multi-valued call.
+const resultsVar = "%results"
 
-func (conv *converter) quoAssign(lhs ast.Expr, rhs ast.Expr) sexp.Form {
-	x, y := conv.Expr(lhs), conv.Expr(rhs)
-	return conv.assign(lhs, sexp.NewQuo(x, y))
-}
+// rhsMultiValues converts multi-valued expression.
+// Packed results are bound to a local by returned Bind;
+// returned forms unpack them.
+func (conv *converter) rhsMultiValues(rhs ast.Expr) (*sexp.Bind, []sexp.Form) {
+	var call sexp.Form
+	var tuple *types.Tuple
+	switch rhs := rhs.(type) {
+	case *ast.TypeAssertExpr:
+		call, tuple = conv.typeAssertOk(rhs)
+	case *ast.UnaryExpr:
+		if rhs.Op == token.ARROW {
+			call, tuple = conv.chanRecvOk(conv.Expr(rhs.X))
+		}
+	case *ast.IndexExpr:
+		call, tuple = conv.mapIndexOk(rhs)
+	}
+	if call == nil {
+		call = conv.Expr(rhs)
+		tuple = conv.typeOf(rhs).(*types.Tuple)
+	}
 
-func (conv *converter) rhsMultiValues(rhs ast.Expr) []sexp.Form {
-	tuple := conv.typeOf(rhs).(*types.Tuple)
+	results := sexp.Local{Name: resultsVar, Typ: lisp.TypObject}
 	forms := make([]sexp.Form, tuple.Len())
+	for i := range forms {
+		forms[i] = retValue(results, tuple, i)
+	}
+	return &sexp.Bind{Name: resultsVar, Init: call}, forms
+}
 
-	// First result is evaluated in a normal way.
-	forms[0] = conv.Expr(rhs)
-
-	// Other results are assigned to a global variable.
-	// Index uniquely identifies variable used for storage.
-	for i := 1; i < tuple.Len(); i++ {
-		forms[i] = sexp.Var{
-			Name: rt.RetVars[i],
-			Typ:  tuple.At(i).Type(),
-		}
+// retValue returns i-th result of the call that returned tuple.
+// Results are packed by the callee: pair of results
+// is a cons, more results are stored in a vector.
+// Every call returns new object, so results can not
+// be overwritten by other calls (including recursive ones).
+func retValue(results sexp.Form, tuple *types.Tuple, i int) sexp.Form {
+	var form sexp.Form
+	switch {
+	case tuple.Len() > 2:
+		form = sexp.NewLispCall(lisp.FnAref, results, sexp.Int(i))
+	case i == 0:
+		form = sexp.NewLispCall(lisp.FnCar, results)
+	default:
+		form = sexp.NewLispCall(lisp.FnCdr, results)
 	}
+	return &sexp.TypeCast{Form: form, Typ: tuple.At(i).Type()}
+}
 
-	return forms
+// okTuple returns (typ, bool) tuple of "comma, ok" expressions.
+func okTuple(typ types.Type) *types.Tuple {
+	return types.NewTuple(
+		types.NewVar(token.NoPos, nil, "", typ),
+		types.NewVar(token.NoPos, nil, "", types.Typ[types.Bool]),
+	)
 }
 
 func (conv *converter) multiValueAssign(lhs []ast.Expr, rhs ast.Expr) sexp.FormList {
-	forms := make([]sexp.Form, len(lhs))
-
-	for i, rhs := range conv.rhsMultiValues(rhs) {
-		forms[i] = conv.assign(lhs[i], rhs)
+	bind, values := conv.rhsMultiValues(rhs)
+	forms := []sexp.Form{bind}
+	for i, value := range values {
+		forms = append(forms, conv.assign(lhs[i], value))
 	}
-
 	return sexp.FormList(forms)
 }
 
@@ -108,35 +159,56 @@ func (conv *converter) singleValueAssign(lhs, rhs []ast.Expr) sexp.FormList {
 }
 
 func (conv *converter) assign(lhs ast.Expr, expr sexp.Form) sexp.Form {
+	if isBlankIdent(lhs) {
+		// Copying would hide the call from ignoredExpr.
+		return conv.ignoredExpr(expr)
+	}
+	if typ := conv.typeOf(lhs); isRefPointee(typ) && conv.info.Types[lhs].Addressable() {
+		// Target object may be referenced by pointers,
+		// so it is updated instead of being replaced.
+		return conv.copyInto(conv.Expr(lhs), expr, typ, 0)
+	}
 	expr = conv.copyValue(expr, conv.typeOf(lhs))
 	switch lhs := lhs.(type) {
 	case *ast.Ident:
-		if lhs.Name == blankIdent {
-			return conv.ignoredExpr(expr)
+		if obj := conv.info.Defs[lhs]; obj != nil {
+			if conv.isBoxed(obj) {
+				return &sexp.Bind{Name: lhs.Name, Init: box(expr)}
+			}
+			return &sexp.Bind{Name: lhs.Name, Init: expr}
 		}
-		if conv.info.Defs[lhs] == nil {
-			if xtypes.IsGlobal(conv.info.Uses[lhs]) {
-				return &sexp.VarUpdate{
-					Name: conv.env.InternVar(nil, lhs.Name),
-					Expr: expr,
-				}
+		obj := conv.info.Uses[lhs]
+		if xtypes.IsGlobal(obj) {
+			return &sexp.VarUpdate{
+				Name: conv.env.InternVar(nil, lhs.Name),
+				Expr: expr,
 			}
-			return &sexp.Rebind{Name: lhs.Name, Expr: expr}
 		}
-		return &sexp.Bind{Name: lhs.Name, Init: expr}
+		if conv.isBoxed(obj) {
+			return setBoxed(lhs.Name, obj.Type(), expr)
+		}
+		return &sexp.Rebind{Name: lhs.Name, Expr: expr}
 
 	case *ast.IndexExpr:
-		switch typ := conv.typeOf(lhs.X).(type) {
+		switch typ := conv.typeOf(lhs.X).Underlying().(type) {
 		case *types.Map:
 			return &sexp.ExprStmt{
-				Expr: conv.call(rt.FnMapInsert, lhs.Index, expr, lhs.X),
+				Expr: conv.call(rt.FnMapInsert, conv.mapKey(lhs.Index, typ), expr, lhs.X),
 			}
 
 		case *types.Array:
 			return &sexp.ArrayUpdate{
 				Array: conv.Expr(lhs.X),
 				Index: conv.Expr(lhs.Index),
-				Expr:  uintElem(expr, typ.Elem()),
+				Expr:  conv.uintElem(expr, typ.Elem()),
+			}
+
+		case *types.Pointer:
+			// Pointer to array is the array itself.
+			return &sexp.ArrayUpdate{
+				Array: conv.Expr(lhs.X),
+				Index: conv.Expr(lhs.Index),
+				Expr:  conv.uintElem(expr, typ.Elem().Underlying().(*types.Array).Elem()),
 			}
 
 		case *types.Slice:
@@ -149,23 +221,14 @@ func (conv *converter) assign(lhs ast.Expr, expr sexp.Form) sexp.Form {
 		}
 
 	case *ast.SelectorExpr:
-		typ := conv.typeOf(lhs.X)
-		if typ, ok := typ.Underlying().(*types.Struct); ok {
+		if sel := conv.info.Selections[lhs]; sel != nil {
+			path := sel.Index()
+			x, typ := selectPath(conv.Expr(lhs.X), conv.typeOf(lhs.X), path[:len(path)-1])
 			return &sexp.StructUpdate{
-				Struct: conv.Expr(lhs.X),
-				Index:  xtypes.LookupField(lhs.Sel.Name, typ),
+				Struct: x,
+				Index:  path[len(path)-1],
 				Expr:   expr,
-				Typ:    typ,
-			}
-		}
-		if typ, ok := typ.(*types.Pointer); ok {
-			if derefTyp, ok := typ.Elem().Underlying().(*types.Struct); ok {
-				return &sexp.StructUpdate{
-					Struct: conv.Expr(lhs.X),
-					Index:  xtypes.LookupField(lhs.Sel.Name, derefTyp),
-					Expr:   expr,
-					Typ:    derefTyp,
-				}
+				Typ:    xtypes.MaybeDeref(typ).Underlying().(*types.Struct),
 			}
 		}
 		obj := conv.info.ObjectOf(lhs.Sel)
@@ -174,21 +237,65 @@ func (conv *converter) assign(lhs ast.Expr, expr sexp.Form) sexp.Form {
 			Expr: expr,
 		}
 
-	// #TODO: indirect assign
+	case *ast.StarExpr:
+		return conv.derefAssign(lhs, expr)
+
 	default:
 		panic(exn.Conv(conv.fileSet, "can't assign to", lhs))
 	}
 }
 
+// pureFuncs are Lisp functions without side effects.
+// Their calls can be ignored if arguments can be ignored.
+var pureFuncs = map[*lisp.Func]bool{
+	lisp.FnList:         true,
+	lisp.FnVector:       true,
+	lisp.FnCons:         true,
+	lisp.FnMakeVector:   true,
+	lisp.FnCopySequence: true,
+	lisp.FnConcat:       true,
+	lisp.FnNot:          true,
+	lisp.FnEq:           true,
+	lisp.FnEqual:        true,
+}
+
 func (conv *converter) ignoredExpr(expr sexp.Form) sexp.Form {
 	switch expr := expr.(type) {
-	case *sexp.Call:
+	case *sexp.Call, *sexp.DynCall, *sexp.LambdaCall:
 		// Function call can not be ignored because
 		// it may have side effects.
 		return &sexp.ExprStmt{Expr: expr}
+	case *sexp.TypeCast:
+		return conv.ignoredExpr(expr.Form)
+	case *sexp.LispCall:
+		if expr.Fn == lisp.FnCar {
+			// First result of multi-valued call.
+			return conv.ignoredExpr(expr.Args[0])
+		}
+		if pureFuncs[expr.Fn] {
+			return conv.ignoredParts(expr, expr.Args)
+		}
+		return &sexp.ExprStmt{Expr: expr}
+	case *sexp.ArrayLit:
+		return conv.ignoredParts(expr, expr.Vals)
+	case *sexp.SliceLit:
+		return conv.ignoredParts(expr, expr.Vals)
+	case *sexp.StructLit:
+		return conv.ignoredParts(expr, expr.Vals)
 
 	default:
 		// Ignored completely.
 		return sexp.EmptyForm
 	}
 }
+
+// ignoredParts ignores expr that has no side effects of its own.
+// It is evaluated only if any of its parts can not be ignored.
+func (conv *converter) ignoredParts(expr sexp.Form, parts []sexp.Form) sexp.Form {
+	for _, part := range parts {
+		if conv.ignoredExpr(part) != sexp.EmptyForm {
+			return &sexp.ExprStmt{Expr: expr}
+		}
+	}
+	return sexp.EmptyForm
+}
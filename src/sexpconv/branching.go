@@ -4,21 +4,108 @@ import (
 	"go/ast"
 	"go/token"
 	"sexp"
+	"strconv"
 )
 
+// branchTarget is a statement that "break", "continue"
+// and "fallthrough" statements can refer to.
+//
+// Innermost loop branches are compiled by backend;
+// other branches jump to generated labels.
+// Generated label names contain "/", so they never
+// clash with Go labels.
+type branchTarget struct {
+	label  string // Go label; empty if statement is not labeled
+	isLoop bool
+	id     int
+
+	breakUsed    bool
+	continueUsed bool
+	// Label of the next case clause body; set only
+	// while converting clause that ends with "fallthrough".
+	fallthroughLabel string
+}
+
+func (t *branchTarget) breakLabel() string {
+	return "break/" + strconv.Itoa(t.id)
+}
+
+func (t *branchTarget) continueLabel() string {
+	return "continue/" + strconv.Itoa(t.id)
+}
+
+func (t *branchTarget) caseLabel(i int) string {
+	return "case/" + strconv.Itoa(t.id) + "/" + strconv.Itoa(i)
+}
+
+// pushTarget must be called before loop, switch or select
+// statement conversion; popTarget must be called after it.
+func (conv *converter) pushTarget(isLoop bool) *branchTarget {
+	conv.targetSeq++
+	t := &branchTarget{label: conv.stmtLabel, isLoop: isLoop, id: conv.targetSeq}
+	conv.stmtLabel = ""
+	conv.targets = append(conv.targets, t)
+	return t
+}
+
+// popTarget appends "break" label to the converted statement if needed.
+func (conv *converter) popTarget(form sexp.Form) sexp.Form {
+	t := conv.targets[len(conv.targets)-1]
+	conv.targets = conv.targets[:len(conv.targets)-1]
+	if !t.breakUsed {
+		return form
+	}
+	return sexp.FormList{form, &sexp.Label{Name: t.breakLabel()}}
+}
+
+// loopBody converts loop body.
+// Labeled "continue" from nested loop jumps to the end of the body.
+// The label is placed outside of the body scope, so jumps
+// from before local declarations have matching stack depth.
+func (conv *converter) loopBody(node *ast.BlockStmt) sexp.Block {
+	body := conv.BlockStmt(node)
+	if t := conv.targets[len(conv.targets)-1]; t.continueUsed {
+		return sexp.Block{body, &sexp.Label{Name: t.continueLabel()}}
+	}
+	return body
+}
+
+// findTarget returns statement that is referenced by branch.
+func (conv *converter) findTarget(label *ast.Ident, loopOnly bool) *branchTarget {
+	for i := len(conv.targets) - 1; i >= 0; i-- {
+		t := conv.targets[i]
+		if label != nil {
+			if t.label == label.Name {
+				return t
+			}
+		} else if t.isLoop || !loopOnly {
+			return t
+		}
+	}
+	return nil
+}
+
 func (conv *converter) BranchStmt(node *ast.BranchStmt) sexp.Form {
 	switch node.Tok {
 	case token.CONTINUE:
-		if node.Label != nil {
-			panic(errUnexpectedStmt(conv, node)) // #REFS: 66
+		t := conv.findTarget(node.Label, true)
+		if t == conv.findTarget(nil, true) {
+			return sexp.ContinueGoto
 		}
-		return sexp.ContinueGoto
+		t.continueUsed = true
+		return &sexp.Goto{LabelName: t.continueLabel()}
 
 	case token.BREAK:
-		if node.Label != nil {
-			panic(errUnexpectedStmt(conv, node)) // #REFS: 66
+		t := conv.findTarget(node.Label, false)
+		if t == conv.findTarget(nil, true) {
+			return sexp.BreakGoto
 		}
-		return sexp.BreakGoto
+		t.breakUsed = true
+		return &sexp.Goto{LabelName: t.breakLabel()}
+
+	case token.FALLTHROUGH:
+		t := conv.findTarget(nil, false)
+		return &sexp.Goto{LabelName: t.fallthroughLabel}
 
 	case token.GOTO:
 		return &sexp.Goto{LabelName: node.Label.Name}
@@ -29,8 +116,29 @@ func (conv *converter) BranchStmt(node *ast.BranchStmt) sexp.Form {
 }
 
 func (conv *converter) LabeledStmt(node *ast.LabeledStmt) sexp.Form {
+	switch node.Stmt.(type) {
+	case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+		conv.stmtLabel = node.Label.Name
+	}
 	return sexp.FormList([]sexp.Form{
 		&sexp.Label{Name: node.Label.Name},
 		conv.Stmt(node.Stmt),
 	})
 }
+
+// hasBranches reports whether loop body contains statements
+// that make it impossible to unroll.
+// Function literals are not inspected.
+func hasBranches(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BranchStmt, *ast.LabeledStmt:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
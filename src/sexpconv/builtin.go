@@ -10,8 +10,22 @@ import (
 	"sexp"
 )
 
+// newBuiltin = "new(T)".
+// Non-struct values are allocated inside a box.
+func (conv *converter) newBuiltin(node ast.Expr) sexp.Form {
+	typ := conv.typeOf(node)
+	zv := ZeroValue(typ)
+	if isRefPointee(typ) {
+		return conv.takeAddr(zv)
+	}
+	return &sexp.TypeCast{
+		Form: sexp.NewCall(rt.FnCarPtr, box(zv)),
+		Typ:  types.NewPointer(typ),
+	}
+}
+
 func (conv *converter) lenBuiltin(arg ast.Expr) sexp.Form {
-	switch typ := conv.typeOf(arg).(type) {
+	switch typ := conv.typeOf(arg).Underlying().(type) {
 	case *types.Map:
 		return conv.lispCall(lisp.FnHashTableCount, arg)
 
@@ -21,6 +35,9 @@ func (conv *converter) lenBuiltin(arg ast.Expr) sexp.Form {
 	case *types.Slice:
 		return conv.call(rt.FnSliceLen, arg)
 
+	case *types.Chan:
+		return conv.call(rt.FnChanLen, arg)
+
 	case *types.Basic:
 		assert.True(typ.Kind() == types.String)
 		return conv.lispCall(lisp.FnStringBytes, arg)
@@ -31,25 +48,29 @@ func (conv *converter) lenBuiltin(arg ast.Expr) sexp.Form {
 }
 
 func (conv *converter) capBuiltin(arg ast.Expr) sexp.Form {
-	switch typ := conv.typeOf(arg).(type) {
+	switch typ := conv.typeOf(arg).Underlying().(type) {
 	case *types.Array:
 		return sexp.Int(typ.Len())
 
 	case *types.Slice:
 		return conv.call(rt.FnSliceCap, arg)
 
+	case *types.Chan:
+		return conv.call(rt.FnChanCap, arg)
+
 	default:
 		panic(exn.Conv(conv.fileSet, "can't apply cap", arg))
 	}
 }
 
 func (conv *converter) makeBuiltin(args []ast.Expr) sexp.Form {
-	switch typ := conv.typeOf(args[0]).(type) {
+	switch typ := conv.typeOf(args[0]).Underlying().(type) {
 	case *types.Map:
+		test := conv.mapKeyTest(typ.Key())
 		if len(args) == 2 {
-			return conv.call(rt.FnMakeMapCap, args[1])
+			return conv.call(rt.FnMakeMapCap, args[1], test)
 		}
-		return conv.call(rt.FnMakeMap)
+		return conv.call(rt.FnMakeMap, test)
 
 	case *types.Slice:
 		zv := ZeroValue(typ.Elem())
@@ -58,18 +79,37 @@ func (conv *converter) makeBuiltin(args []ast.Expr) sexp.Form {
 		}
 		return conv.call(rt.FnMakeSliceCap, args[1], args[2], zv)
 
+	case *types.Chan:
+		zv := ZeroValue(typ.Elem())
+		if len(args) == 2 {
+			return conv.call(rt.FnMakeChan, args[1], zv)
+		}
+		return conv.call(rt.FnMakeChan, sexp.Int(0), zv)
+
 	default:
 		panic(exn.Conv(conv.fileSet, "can't make", args[0]))
 	}
 }
 
-func (conv *converter) appendBuiltin(args []ast.Expr) sexp.Form {
-	if len(args) != 2 {
-		panic(exn.NoImpl("variadic append"))
+// appendBuiltin = "append(slice, vals...)".
+// Multiple values are pushed one by one;
+// "append(dst, src...)" copies all elements at once.
+func (conv *converter) appendBuiltin(node *ast.CallExpr) sexp.Form {
+	args := node.Args
+	if node.Ellipsis.IsValid() {
+		src := conv.typeOf(args[1]).Underlying()
+		if typ, ok := src.(*types.Basic); ok && typ.Info()&types.IsString != 0 {
+			return conv.call(rt.FnSliceAppendStr, args[0], args[1])
+		}
+		return conv.call(rt.FnSliceAppend, args[0], args[1])
 	}
 
-	slice := args[0]
-	dstTyp := conv.typeOf(slice).(*types.Slice).Elem()
-	x := conv.copyValue(conv.Expr(args[1]), dstTyp)
-	return conv.call(rt.FnSlicePush, slice, x)
+	dstTyp := conv.typeOf(args[0]).Underlying().(*types.Slice).Elem()
+	slice := conv.Expr(args[0])
+	for _, arg := range args[1:] {
+		conv.ctxType = dstTyp
+		x := conv.copyValue(conv.Expr(arg), dstTyp)
+		slice = conv.call(rt.FnSlicePush, slice, x)
+	}
+	return slice
 }
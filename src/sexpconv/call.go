@@ -1,8 +1,6 @@
 package sexpconv
 
 import (
-	"assert"
-	"exn"
 	"go/ast"
 	"go/types"
 	"magic_pkg/emacs/lisp"
@@ -33,6 +31,32 @@ func (conv *converter) uniList(xs []interface{}) []sexp.Form {
 	return res
 }
 
+// argList converts Go function call arguments.
+// Variadic arguments are packed into a slice,
+// unless the call passes existing slice as "xs...".
+// Empty variadic argument list is passed as empty slice.
+func (conv *converter) argList(sig *types.Signature, node *ast.CallExpr) []sexp.Form {
+	params := sig.Params()
+	pack := sig.Variadic() && !node.Ellipsis.IsValid()
+	forms := make([]sexp.Form, 0, params.Len())
+	var vals []sexp.Form
+	for i, arg := range node.Args {
+		if pack && i >= params.Len()-1 {
+			typ := params.At(params.Len() - 1).Type().(*types.Slice).Elem()
+			conv.ctxType = typ
+			vals = append(vals, conv.copyValue(conv.Expr(arg), typ))
+			continue
+		}
+		conv.ctxType = params.At(i).Type()
+		forms = append(forms, conv.Expr(arg))
+	}
+	if pack {
+		typ := params.At(params.Len() - 1).Type()
+		forms = append(forms, &sexp.SliceLit{Vals: vals, Typ: typ.(*types.Slice)})
+	}
+	return forms
+}
+
 func (conv *converter) copyArgList(args []sexp.Form, ifaceTypes map[int]types.Type) {
 	for i, arg := range args {
 		args[i] = conv.copyValue(arg, ifaceTypes[i])
@@ -49,92 +73,131 @@ func (conv *converter) lispCall(fn *lisp.Func, args ...interface{}) *sexp.LispCa
 	return conv.lispApply(fn, conv.uniList(args))
 }
 
-func (conv *converter) typeCast(node ast.Expr, typ types.Type) *sexp.TypeCast {
-	return &sexp.TypeCast{Form: conv.Expr(node), Typ: typ}
+// methodRecv returns receiver of the selected method along with
+// type that declares that method.
+// Promoted methods are called with embedded field as a receiver.
+func (conv *converter) methodRecv(x sexp.Form, typ types.Type, sel *types.Selection) (sexp.Form, *types.Named) {
+	path := sel.Index()
+	x, typ = selectPath(x, typ, path[:len(path)-1])
+	method := sel.Obj().Type().(*types.Signature)
+	if _, ok := method.Recv().Type().(*types.Pointer); ok && xtypes.IsStruct(x.Type()) {
+		// Pointer receiver must refer to the addressable value itself.
+		x = conv.takeAddr(x)
+	}
+	return x, xtypes.AsNamedType(typ)
 }
 
-var typeCasts = map[string]types.Type{
-	"uint":   xtypes.TypUint,
-	"uint8":  xtypes.TypUint8,
-	"byte":   xtypes.TypUint8,
-	"uint16": xtypes.TypUint16,
-	"uint32": xtypes.TypUint32,
-	"uint64": xtypes.TypUint64,
-
-	"int":   xtypes.TypInt,
-	"int8":  xtypes.TypInt8,
-	"int16": xtypes.TypInt16,
-	"int32": xtypes.TypInt32,
-	"rune":  xtypes.TypInt32,
-	"int64": xtypes.TypInt64,
-
-	"float32": xtypes.TypFloat32,
-	"float64": xtypes.TypFloat64,
-
-	"bool": xtypes.TypBool,
+// ifaceCall creates interface (polymorphic) method call.
+// Runtime call helpers take lisp.Object arguments,
+// so values are copied according to method signature.
+func (conv *converter) ifaceCall(x sexp.Form, recv *types.Named, name string, sig *types.Signature, argForms []sexp.Form) sexp.Form {
+	for i, arg := range argForms {
+		argForms[i] = conv.copyValue(arg, sig.Params().At(i).Type())
+	}
+	iface := recv.Underlying().(*types.Interface)
+	fnID := sexp.Int(xtypes.LookupIfaceMethod(name, iface))
+	if len(argForms) >= len(rt.FnIfaceCall) {
+		return conv.call(rt.FnIfaceCallN, x, fnID, sexp.NewLispCall(lisp.FnList, argForms...))
+	}
+	return &sexp.Call{
+		Fn:   rt.FnIfaceCall[len(argForms)],
+		Args: append([]sexp.Form{x, fnID}, argForms...),
+	}
+}
+
+// recvExpr converts selected method receiver expression.
+func (conv *converter) recvExpr(node *ast.SelectorExpr, sel *types.Selection) sexp.Form {
+	if needsRecvAddr(conv.info, node, sel) {
+		return conv.addrOf(node.X)
+	}
+	return conv.Expr(node.X)
+}
+
+// needsRecvAddr reports whether selected method has pointer
+// receiver while the selector operand is a value which is
+// not represented by reference (for example, named integer).
+func needsRecvAddr(info *types.Info, node *ast.SelectorExpr, sel *types.Selection) bool {
+	if sel.Kind() != types.MethodVal || len(sel.Index()) != 1 {
+		return false
+	}
+	if _, ok := sel.Obj().Type().(*types.Signature).Recv().Type().(*types.Pointer); !ok {
+		return false
+	}
+	typ := info.TypeOf(node.X)
+	_, isPtr := typ.(*types.Pointer)
+	return !isPtr && !types.IsInterface(typ) && !isRefPointee(typ)
 }
 
 func (conv *converter) CallExpr(node *ast.CallExpr) sexp.Form {
+	if conv.info.Types[node.Fun].IsType() {
+		return conv.conversion(node)
+	}
+	fun := node.Fun
+	if x := conv.instantiatedFunc(fun); x != nil {
+		fun = x
+	}
 	// #REFS: 2.
-	switch args := node.Args; fn := node.Fun.(type) {
+	switch args := node.Args; fn := fun.(type) {
 	case *ast.SelectorExpr: // x.sel()
 		sel := conv.info.Selections[fn]
+		if sel != nil && sel.Kind() != types.MethodVal {
+			// Function-typed field or method expression.
+			return conv.dynCall(node)
+		}
 		if sel != nil {
-			recv := xtypes.AsNamedType(sel.Recv())
-			if recv == lisp.TypObject {
+			if xtypes.AsNamedType(sel.Recv()) == lisp.TypObject {
 				return conv.lispObjectMethod(fn.Sel.Name, fn.X, args)
 			}
+			x, recv := conv.methodRecv(conv.recvExpr(fn, sel), conv.typeOf(fn.X), sel)
+			sig := conv.typeOf(fn).(*types.Signature)
+			argForms := conv.argList(sig, node)
 			if !types.IsInterface(recv) {
 				// Direct method call.
 				return conv.apply(
-					conv.ftab.LookupMethod(recv.Obj(), fn.Sel.Name),
-					conv.exprList(append([]ast.Expr{fn.X}, args...)),
+					conv.methodFunc(recv, fn.Sel.Name),
+					append([]sexp.Form{x}, argForms...),
 				)
 			}
-			// Interface (polymorphic) method call.
-			if len(args) >= len(rt.FnIfaceCall) {
-				panic(exn.NoImpl("interface method call with more than %d arguments", len(rt.FnIfaceCall)-1))
-			}
-			iface := recv.Underlying().(*types.Interface)
-			return conv.apply(
-				rt.FnIfaceCall[len(args)],
-				append([]sexp.Form{
-					conv.Expr(fn.X),
-					sexp.Int(xtypes.LookupIfaceMethod(fn.Sel.Name, iface)),
-				}, conv.exprList(args)...),
-			)
+			return conv.ifaceCall(x, recv, fn.Sel.Name, sig, argForms)
 		}
 
 		pkg := fn.X.(*ast.Ident)
 		if pkg.Name == "lisp" {
+			if node.Ellipsis.IsValid() {
+				return conv.lispSpreadCall(fn.Sel.Name, node)
+			}
 			return conv.intrinFuncCall(fn.Sel.Name, args)
 		}
+		if _, ok := conv.info.Uses[fn.Sel].(*types.Var); ok {
+			return conv.dynCall(node)
+		}
 
-		return conv.callOrCoerce(conv.info.ObjectOf(fn.Sel).Pkg(), fn.Sel, args)
+		return conv.funcCall(fn.Sel, node)
 
 	case *ast.Ident: // f()
-		if castTyp := typeCasts[fn.Name]; castTyp != nil {
-			return conv.typeCast(args[0], castTyp)
+		if _, ok := conv.info.Uses[fn].(*types.Var); ok {
+			return conv.dynCall(node)
 		}
-
 		switch fn.Name {
-		case "string":
-			// #REFS: 26.
-			return conv.call(rt.FnBytesToStr, args[0])
-
 		case "make":
 			return conv.makeBuiltin(args)
+		case "new":
+			return conv.newBuiltin(args[0])
 		case "len":
 			return conv.lenBuiltin(args[0])
 		case "cap":
 			return conv.capBuiltin(args[0])
 		case "append":
-			return conv.appendBuiltin(args)
+			return conv.appendBuiltin(node)
 		case "copy":
 			dst, src := args[0], args[1]
 			return conv.call(rt.FnSliceCopy, dst, src)
+		case "close":
+			return conv.call(rt.FnChanClose, args[0])
 		case "panic":
 			return conv.call(rt.FnPanic, args[0])
+		case "recover":
+			return conv.call(rt.FnRecover)
 		case "print", "println":
 			// #REFS: 35.
 			argList := &sexp.LispCall{
@@ -146,41 +209,23 @@ func (conv *converter) CallExpr(node *ast.CallExpr) sexp.Form {
 			}
 			return conv.call(rt.FnPrintln, argList)
 		case "delete":
-			key, m := args[0], args[1]
-			return conv.lispCall(lisp.FnRemhash, m, key)
+			m, key := args[0], args[1]
+			typ := conv.typeOf(m).Underlying().(*types.Map)
+			return conv.lispCall(lisp.FnRemhash, conv.mapKey(key, typ), m)
 
 		default:
-			return conv.callOrCoerce(conv.ftab.MasterPkg(), fn, args)
+			return conv.funcCall(fn, node)
 		}
 
-	case *ast.ArrayType:
-		// Currently, support only "[]byte(s)" expressions.
-		typ := conv.typeOf(fn).(*types.Slice)
-		elemTyp := typ.Elem().(*types.Basic)
-		assert.True(elemTyp.Kind() == types.Byte)
-		return conv.apply(rt.FnStrToBytes, conv.exprList(args))
-
 	default:
+		if _, ok := conv.typeOf(fn).Underlying().(*types.Signature); ok {
+			return conv.dynCall(node)
+		}
 		panic(errUnexpectedExpr(conv, node))
 	}
 }
 
-func (conv *converter) callOrCoerce(p *types.Package, id *ast.Ident, args []ast.Expr) sexp.Form {
-	fn := conv.ftab.LookupFunc(p, id.Name)
-	if fn != nil {
-		// Call.
-		return conv.apply(fn, conv.exprList(args))
-	}
-	// Coerce.
-	arg := conv.Expr(args[0])
-	dstTyp := arg.Type()
-	typ := conv.typeOf(id)
-	if types.Identical(typ, dstTyp) {
-		return arg // Optimization to avoid redundant TypeCast object
-	}
-	if _, ok := typ.Underlying().(*types.Struct); ok {
-		// #REFS: 44.
-		panic(exn.NoImpl("struct conversions"))
-	}
-	return &sexp.TypeCast{Form: arg, Typ: dstTyp}
+func (conv *converter) funcCall(id *ast.Ident, node *ast.CallExpr) sexp.Form {
+	fn, sig := conv.funcRef(id)
+	return conv.apply(fn, conv.argList(sig, node))
 }
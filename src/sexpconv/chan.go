@@ -0,0 +1,155 @@
+package sexpconv
+
+import (
+	"go/ast"
+	"go/types"
+	"magic_pkg/emacs/lisp"
+	"magic_pkg/emacs/rt"
+	"sexp"
+)
+
+// GoStmt starts a new Emacs thread that executes the call.
+func (conv *converter) GoStmt(node *ast.GoStmt) sexp.Form {
+	return &sexp.ExprStmt{
+		Expr: sexp.NewLispCall(lisp.FnMakeThread, conv.thunk(node.Call)),
+	}
+}
+
+func (conv *converter) SendStmt(node *ast.SendStmt) sexp.Form {
+	typ := conv.typeOf(node.Chan).Underlying().(*types.Chan)
+	conv.ctxType = typ.Elem()
+	val := conv.copyValue(conv.Expr(node.Value), typ.Elem())
+	return &sexp.ExprStmt{
+		Expr: conv.call(rt.FnChanSend, node.Chan, val),
+	}
+}
+
+// chanRecv converts "<-ch" expression.
+func (conv *converter) chanRecv(ch sexp.Form) sexp.Form {
+	call, tuple := conv.chanRecvOk(ch)
+	return retValue(call, tuple, 0)
+}
+
+// chanRecvOk converts "v, ok := <-ch" form of receive.
+// Returned call packs both results.
+func (conv *converter) chanRecvOk(ch sexp.Form) (sexp.Form, *types.Tuple) {
+	typ := ch.Type().Underlying().(*types.Chan)
+	return conv.call(rt.FnChanRecv, ch), okTuple(typ.Elem())
+}
+
+// foreachChan receives values until channel is closed.
+func (conv *converter) foreachChan(node *ast.RangeStmt) sexp.Form {
+	ch := sexp.Local{Name: "_ch", Typ: conv.typeOf(node.X)}
+	call, tuple := conv.chanRecvOk(ch)
+	results := sexp.Local{Name: resultsVar, Typ: lisp.TypObject}
+
+	var val sexp.Form = sexp.EmptyForm
+	if node.Key != nil {
+		val = conv.assign(node.Key, retValue(results, tuple, 0))
+	}
+	body := sexp.Block{
+		&sexp.Bind{Name: resultsVar, Init: call},
+		&sexp.If{
+			Cond: sexp.NewNot(retValue(results, tuple, 1)),
+			Then: sexp.Block{sexp.BreakGoto},
+			Else: sexp.EmptyForm,
+		},
+		val,
+		conv.loopBody(node.Body),
+	}
+
+	return sexp.Block{
+		&sexp.Bind{Name: ch.Name, Init: conv.Expr(node.X)},
+		&sexp.Loop{Init: sexp.EmptyForm, Post: sexp.EmptyForm, Body: body},
+	}
+}
+
+// Locals that hold selected case index and received value.
+const (
+	selectIndex = "_si"
+	selectVal   = "_sv"
+	selectOk    = "_sok"
+)
+
+// SelectStmt converts "select" into rt.Select call followed
+// by switch over selected case index.
+func (conv *converter) SelectStmt(node *ast.SelectStmt) sexp.Form {
+	conv.pushTarget(false)
+	return conv.popTarget(conv.selectStmt(node))
+}
+
+func (conv *converter) selectStmt(node *ast.SelectStmt) sexp.Form {
+	// Channel and value operands are evaluated
+	// once, in source order.
+	env := &tmpEnv{strict: true}
+	var chans, vals []sexp.Form
+	hasDefault := false
+	defaultBody := sexp.EmptyBlock
+	clauses := make([]sexp.CaseClause, 0, len(node.Body.List))
+
+	for _, cc := range node.Body.List {
+		cc := cc.(*ast.CommClause)
+		if cc.Comm == nil {
+			hasDefault = true
+			defaultBody = sexp.Block(conv.stmtList(cc.Body))
+			continue
+		}
+
+		var body []sexp.Form
+		switch comm := cc.Comm.(type) {
+		case *ast.SendStmt:
+			typ := conv.typeOf(comm.Chan).Underlying().(*types.Chan)
+			conv.ctxType = typ.Elem()
+			chans = append(chans, env.bind(conv.Expr(comm.Chan)))
+			val := env.bind(conv.copyValue(conv.Expr(comm.Value), typ.Elem()))
+			vals = append(vals, sexp.NewLispCall(lisp.FnList, val))
+
+		case *ast.ExprStmt:
+			recv := comm.X.(*ast.UnaryExpr)
+			chans = append(chans, env.bind(conv.Expr(recv.X)))
+			vals = append(vals, sexp.Nil)
+
+		case *ast.AssignStmt:
+			recv := comm.Rhs[0].(*ast.UnaryExpr)
+			typ := conv.typeOf(recv.X).Underlying().(*types.Chan)
+			chans = append(chans, env.bind(conv.Expr(recv.X)))
+			vals = append(vals, sexp.Nil)
+			val := &sexp.TypeCast{
+				Form: sexp.Local{Name: selectVal, Typ: lisp.TypObject},
+				Typ:  typ.Elem(),
+			}
+			body = append(body, conv.assign(comm.Lhs[0], val))
+			if len(comm.Lhs) == 2 {
+				ok := sexp.Local{Name: selectOk, Typ: types.Typ[types.Bool]}
+				body = append(body, conv.assign(comm.Lhs[1], ok))
+			}
+		}
+
+		clauses = append(clauses, sexp.CaseClause{
+			Expr: sexp.Int(len(chans) - 1),
+			Body: sexp.Block(append(body, conv.stmtList(cc.Body)...)),
+		})
+	}
+
+	sel := conv.call(
+		rt.FnSelect,
+		conv.call(rt.FnArrayToSlice, sexp.NewLispCall(lisp.FnVector, chans...)),
+		sexp.NewLispCall(lisp.FnVector, vals...),
+		sexp.Bool(!hasDefault),
+	)
+	results := sexp.Local{Name: resultsVar, Typ: lisp.TypObject}
+	tuple := rt.FnSelect.Results
+	return sexp.Block(append(env.forms,
+		&sexp.Bind{Name: resultsVar, Init: sel},
+		&sexp.Bind{Name: selectIndex, Init: retValue(results, tuple, 0)},
+		&sexp.Bind{Name: selectVal, Init: retValue(results, tuple, 1)},
+		&sexp.Bind{Name: selectOk, Init: retValue(results, tuple, 2)},
+		&sexp.Switch{
+			Expr: sexp.Local{Name: selectIndex, Typ: types.Typ[types.Int]},
+			SwitchBody: sexp.SwitchBody{
+				Clauses:     clauses,
+				DefaultBody: defaultBody,
+			},
+		},
+	))
+}
@@ -0,0 +1,98 @@
+package sexpconv
+
+import (
+	"go/ast"
+	"go/types"
+	"magic_pkg/emacs/lisp"
+	"magic_pkg/emacs/rt"
+	"sexp"
+)
+
+// Sizes of integer types; used to elide redundant truncation.
+var intSizes = types.StdSizes{WordSize: 8, MaxAlign: 8}
+
+// conversion converts "T(x)" expression.
+func (conv *converter) conversion(node *ast.CallExpr) sexp.Form {
+	typ := conv.typeOf(node.Fun)
+	if cv := sexpConst(conv, node); cv != nil {
+		if types.Identical(cv.Type(), typ) {
+			return cv
+		}
+		return &sexp.TypeCast{Form: cv, Typ: typ}
+	}
+	arg := node.Args[0]
+	if conv.info.Types[arg].IsNil() {
+		return nilValue(typ)
+	}
+	srcTyp := conv.typeOf(arg)
+	x := conv.Expr(arg)
+
+	switch {
+	case types.IsInterface(typ):
+		return &sexp.TypeCast{Form: conv.copyValue(x, typ), Typ: typ}
+
+	case isInteger(srcTyp) && isFloat(typ):
+		return &sexp.TypeCast{Form: sexp.NewLispCall(lisp.FnFloat, x), Typ: typ}
+	case isFloat(srcTyp) && isInteger(typ):
+		// Truncation is performed towards zero.
+		return conv.intConversion(sexp.NewLispCall(lisp.FnTruncate, x), nil, typ)
+	case isInteger(srcTyp) && isInteger(typ):
+		return conv.intConversion(x, srcTyp, typ)
+
+	case isInteger(srcTyp) && isString(typ):
+		return &sexp.TypeCast{Form: conv.call(rt.FnRuneToStr, x), Typ: typ}
+	case isString(srcTyp) && isString(typ):
+		return &sexp.TypeCast{Form: x, Typ: typ}
+	case isString(srcTyp):
+		if isRuneSlice(typ) {
+			return &sexp.TypeCast{Form: conv.call(rt.FnStrToRunes, x), Typ: typ}
+		}
+		return &sexp.TypeCast{Form: conv.call(rt.FnStrToBytes, x), Typ: typ}
+	case isString(typ):
+		if isRuneSlice(srcTyp) {
+			return &sexp.TypeCast{Form: conv.call(rt.FnRunesToStr, x), Typ: typ}
+		}
+		return &sexp.TypeCast{Form: conv.call(rt.FnBytesToStr, x), Typ: typ}
+
+	default:
+		// Types with identical underlying types share representation.
+		// Struct field tags are ignored, so fields layout is the same.
+		return &sexp.TypeCast{Form: x, Typ: typ}
+	}
+}
+
+// intConversion truncates x to the range of integer typ.
+// Nil srcTyp means that x has unknown range.
+//
+// Unsigned results are truncated unless source type
+// range fits into typ range.
+// Signed results are truncated only in wraparound mode.
+func (conv *converter) intConversion(x sexp.Form, srcTyp, typ types.Type) sexp.Form {
+	if conv.pkg.Wraparound {
+		return conv.wrapInt(&sexp.TypeCast{Form: x, Typ: typ}, typ)
+	}
+	if isSigned(typ) || (srcTyp != nil && isUnsigned(srcTyp) &&
+		intSizes.Sizeof(srcTyp) <= intSizes.Sizeof(typ)) {
+		return &sexp.TypeCast{Form: x, Typ: typ}
+	}
+	return &sexp.TypeCast{Form: conv.uintElem(x, typ.Underlying()), Typ: typ}
+}
+
+func isString(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+func isUnsigned(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsUnsigned != 0
+}
+
+func isRuneSlice(typ types.Type) bool {
+	slice, ok := typ.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+	elem, ok := slice.Elem().Underlying().(*types.Basic)
+	return ok && elem.Kind() == types.Int32
+}
@@ -48,11 +48,26 @@ func (conv *converter) copyValue(form sexp.Form, dstTyp types.Type) sexp.Form {
 	}
 
 	if dstTyp != nil && types.IsInterface(dstTyp) {
+		if xtypes.IsEmptyInterface(dstTyp) {
+			// Like lisp.Object, "interface{}" holds values as is,
+			// unless their dynamic type can not be recovered.
+			if isTaggedType(typ) {
+				itab := conv.itabEnv.Intern(typ, nil)
+				return sexp.NewCall(rt.FnMakeIface, sexp.Var{Name: itab, Typ: lisp.TypObject}, res)
+			}
+			return res
+		}
 		dstTyp := dstTyp.(*types.Named)
 		if dstTyp.Obj().Pkg() == lisp.Package || types.Identical(typ, dstTyp) {
 			return res
 		}
-		itab := conv.itabEnv.Intern(xtypes.AsNamedType(typ), dstTyp)
+		if types.IsInterface(typ) {
+			// Interface-to-interface conversion; itab is
+			// selected during run time.
+			return conv.call(rt.FnConvIface, res, conv.ifaceDesc(dstTyp))
+		}
+		conv.instantiateMethods(xtypes.AsNamedType(typ), dstTyp.Underlying().(*types.Interface))
+		itab := conv.itabEnv.Intern(typ, dstTyp)
 		return sexp.NewCall(
 			rt.FnMakeIface,
 			sexp.Var{Name: itab, Typ: lisp.TypObject},
@@ -0,0 +1,197 @@
+package sexpconv
+
+import (
+	"go/ast"
+	"go/types"
+	"magic_pkg/emacs/lisp"
+	"magic_pkg/emacs/rt"
+	"sexp"
+	"strconv"
+	"xtypes"
+)
+
+// Locals that are introduced inside functions that use "defer".
+const (
+	defersVar    = "%defers"     // List of deferred calls (LIFO)
+	panicVar     = "%panic"      // Error object; nil if function is not panicking
+	prevPanicVar = "%prev-panic" // Panic that was handled by the caller
+	retLabel     = "return"      // Jump target for "return" statements
+)
+
+// hasDefer reports whether function body contains "defer" statements.
+// Function literals are not inspected.
+func hasDefer(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.DeferStmt:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+func resultVar(i int) string {
+	return "%r" + strconv.Itoa(i)
+}
+
+// isNamedResult reports whether result can be referenced by name.
+func isNamedResult(v *types.Var) bool {
+	return v.Name() != "" && v.Name() != blankIdent
+}
+
+// bindResults declares named results as locals
+// that are initialized with zero values.
+func (conv *converter) bindResults(results *types.Tuple) []sexp.Form {
+	var forms []sexp.Form
+	for i := 0; i < results.Len(); i++ {
+		v := results.At(i)
+		if !isNamedResult(v) {
+			continue
+		}
+		init := ZeroValue(v.Type())
+		if conv.boxed[v] {
+			init = box(init)
+		}
+		forms = append(forms, &sexp.Bind{Name: v.Name(), Init: init})
+	}
+	return forms
+}
+
+// resultValue returns current value of the i-th result.
+// Unnamed results of functions that do not use "defer"
+// have no storage, so they are always zero.
+func (conv *converter) resultValue(i int) sexp.Form {
+	v := conv.retType.At(i)
+	switch {
+	case isNamedResult(v) && conv.boxed[v]:
+		return unbox(v.Name(), v.Type())
+	case isNamedResult(v):
+		return sexp.Local{Name: v.Name(), Typ: v.Type()}
+	case conv.deferring:
+		return sexp.Local{Name: resultVar(i), Typ: v.Type()}
+	default:
+		return ZeroValue(v.Type())
+	}
+}
+
+// setResult assigns i-th result storage.
+func (conv *converter) setResult(i int, expr sexp.Form) sexp.Form {
+	v := conv.retType.At(i)
+	switch {
+	case isNamedResult(v) && conv.boxed[v]:
+		return setBoxed(v.Name(), v.Type(), expr)
+	case isNamedResult(v):
+		return &sexp.Rebind{Name: v.Name(), Expr: expr}
+	default:
+		return &sexp.Rebind{Name: resultVar(i), Expr: expr}
+	}
+}
+
+// deferBody converts body of the function that uses "defer".
+//
+// Function body is executed under error handler.
+// Results are stored inside locals, so "return" becomes
+// assignment followed by jump to the epilogue.
+// Named results are stored inside their own variables,
+// so deferred calls can modify them.
+// Epilogue runs deferred calls and returns stored results.
+// Each deferred call is executed under its own error handler,
+// so its panic replaces the current one and remaining
+// deferred calls are executed anyway.
+func (conv *converter) deferBody(results *types.Tuple, node *ast.BlockStmt) []sexp.Form {
+	forms := []sexp.Form{
+		&sexp.Bind{Name: defersVar, Init: sexp.Nil},
+		&sexp.Bind{Name: panicVar, Init: sexp.Nil},
+	}
+	retForms := make([]sexp.Form, results.Len())
+	for i := 0; i < results.Len(); i++ {
+		if v := results.At(i); !isNamedResult(v) {
+			forms = append(forms, &sexp.Bind{Name: resultVar(i), Init: ZeroValue(v.Type())})
+		}
+		retForms[i] = conv.resultValue(i)
+	}
+
+	body := sexp.Block{
+		sexp.Block(conv.stmtList(node.List)),
+		&sexp.Label{Name: retLabel},
+	}
+	defers := sexp.Local{Name: defersVar, Typ: lisp.TypObject}
+	panicErr := sexp.Local{Name: panicVar, Typ: lisp.TypObject}
+	runDefers := &sexp.While{
+		Init: sexp.EmptyForm,
+		Cond: sexp.NewNot(sexp.NewNot(defers)),
+		Post: &sexp.Rebind{Name: defersVar, Expr: sexp.NewLispCall(lisp.FnCdr, defers)},
+		Body: sexp.Block{
+			&sexp.Rebind{Name: panicVar, Expr: sexp.Nil},
+			&sexp.Protect{
+				Body: sexp.Block{&sexp.ExprStmt{Expr: &sexp.DynCall{
+					Callable: sexp.NewLispCall(lisp.FnCar, defers),
+					Typ:      xtypes.EmptyTuple,
+				}}},
+				ErrVar: panicVar,
+			},
+			&sexp.ExprStmt{Expr: sexp.NewCall(rt.FnDeferPanic, panicErr)},
+		},
+	}
+	return append(forms,
+		&sexp.Protect{Body: body, ErrVar: panicVar},
+		&sexp.Bind{Name: prevPanicVar, Init: sexp.NewCall(rt.FnDeferBegin, panicErr)},
+		runDefers,
+		&sexp.ExprStmt{Expr: sexp.NewCall(
+			rt.FnDeferEnd,
+			sexp.Local{Name: prevPanicVar, Typ: lisp.TypObject},
+		)},
+		&sexp.Return{Results: retForms},
+	)
+}
+
+// deferReturn stores results and jumps to the function epilogue.
+// Naked return only jumps to the epilogue.
+func (conv *converter) deferReturn(node *ast.ReturnStmt) sexp.Form {
+	if len(node.Results) == 0 {
+		return &sexp.Goto{LabelName: retLabel}
+	}
+
+	var forms, results []sexp.Form
+	if len(node.Results) == 1 && conv.retType.Len() > 1 {
+		var bind *sexp.Bind
+		bind, results = conv.rhsMultiValues(node.Results[0])
+		for i := range results {
+			results[i] = conv.copyValue(results[i], conv.retType.At(i).Type())
+		}
+		forms = append(forms, bind)
+	} else {
+		results = make([]sexp.Form, len(node.Results))
+		for i, node := range node.Results {
+			typ := conv.retType.At(i).Type()
+			conv.ctxType = typ
+			results[i] = conv.copyValue(conv.Expr(node), typ)
+		}
+	}
+
+	// Named results may be referenced by other results
+	// ("return b, a"), so all values are computed first.
+	env := &tmpEnv{strict: true}
+	if isNamedResult(conv.retType.At(0)) && len(results) > 1 {
+		for i := range results {
+			results[i] = env.bind(results[i])
+		}
+	}
+	forms = append(forms, env.forms...)
+	for i, result := range results {
+		forms = append(forms, conv.setResult(i, result))
+	}
+	return sexp.Block(append(forms, &sexp.Goto{LabelName: retLabel}))
+}
+
+func (conv *converter) DeferStmt(node *ast.DeferStmt) sexp.Form {
+	defers := sexp.Local{Name: defersVar, Typ: lisp.TypObject}
+	return &sexp.Rebind{
+		Name: defersVar,
+		Expr: sexp.NewLispCall(lisp.FnCons, conv.thunk(node.Call), defers),
+	}
+}
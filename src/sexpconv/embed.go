@@ -0,0 +1,68 @@
+package sexpconv
+
+import (
+	"go/types"
+	"sexp"
+	"strconv"
+	"tu/symbols"
+	"xast"
+)
+
+// PromotedMethod creates a function that forwards method call
+// to the embedded field that declares the method.
+//
+// Promoted methods are called directly where possible;
+// generated function is referenced by itabs and method expressions.
+// Methods of embedded interfaces are forwarded by interface call.
+// Returns nil for methods that can not be forwarded (Lisp methods).
+func (conv *Converter) PromotedMethod(p *xast.Package, typ *types.Named, sel *types.Selection) *sexp.Func {
+	sig := sel.Obj().Type().(*types.Signature)
+	recvTyp := types.NewPointer(typ)
+	c := conv.newConverter(p)
+	recv, named := c.methodRecv(sexp.Local{Name: "recv", Typ: recvTyp}, recvTyp, sel)
+	var method *sexp.Func
+	if !types.IsInterface(named) {
+		method = conv.ftab.LookupMethod(named.Obj(), sel.Obj().Name())
+		if method == nil {
+			return nil
+		}
+	}
+
+	fn := &sexp.Func{
+		Name:   symbols.MangleMethod(p.FullName, typ.Obj().Name(), sel.Obj().Name()),
+		Params: make([]string, 0, sig.Params().Len()+1),
+	}
+	fn.Params = append(fn.Params, "recv")
+	args := make([]sexp.Form, 0, sig.Params().Len())
+	for i := 0; i < sig.Params().Len(); i++ {
+		// Parameters may be unnamed or blank.
+		name := "arg" + strconv.Itoa(i)
+		fn.Params = append(fn.Params, name)
+		args = append(args, sexp.Local{Name: name, Typ: sig.Params().At(i).Type()})
+	}
+
+	var call sexp.Form
+	if method == nil {
+		fn.Results = sig.Results()
+		for i := 0; i < sig.Params().Len(); i++ {
+			if typ := sig.Params().At(i).Type(); types.IsInterface(typ) {
+				if fn.InterfaceInputs == nil {
+					fn.InterfaceInputs = make(map[int]types.Type)
+				}
+				fn.InterfaceInputs[i+1] = typ
+			}
+		}
+		call = c.ifaceCall(recv, named, sel.Obj().Name(), sig, args)
+	} else {
+		fn.Results = method.Results
+		fn.InterfaceInputs = method.InterfaceInputs
+		call = &sexp.Call{Fn: method, Args: append([]sexp.Form{recv}, args...)}
+	}
+	if fn.Results.Len() == 0 {
+		fn.Body = sexp.Block{&sexp.ExprStmt{Expr: call}, &sexp.Return{}}
+	} else {
+		// Several results are forwarded packed.
+		fn.Body = sexp.Block{&sexp.Return{Results: []sexp.Form{call}}}
+	}
+	return fn
+}
@@ -0,0 +1,283 @@
+package sexpconv
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"magic_pkg/emacs/lisp"
+	"magic_pkg/emacs/rt"
+	"sexp"
+	"tu/symbols"
+)
+
+// comparison converts "x == y" and "x != y".
+// Returns nil for numeric operands; they are handled
+// together with other numeric operations.
+func (conv *converter) comparison(node *ast.BinaryExpr) sexp.Form {
+	x, y := node.X, node.Y
+	if conv.info.Types[x].IsNil() {
+		x, y = y, x
+	}
+	typ := conv.typeOf(x)
+	if isNumeric(typ) && isNumeric(conv.typeOf(y)) {
+		return nil
+	}
+
+	var eq sexp.Form
+	if conv.info.Types[y].IsNil() {
+		eq = sexp.NewLispCall(lisp.FnEq, conv.Expr(x), nilValue(typ))
+	} else {
+		xForm, yForm := conv.Expr(x), conv.Expr(y)
+		// Non-interface operand is converted to the
+		// interface type of the other operand.
+		if yTyp := conv.typeOf(y); types.IsInterface(yTyp) && !types.IsInterface(typ) {
+			typ = yTyp
+			xForm = conv.copyValue(xForm, typ)
+		} else if types.IsInterface(typ) && !types.IsInterface(yTyp) {
+			yForm = conv.copyValue(yForm, typ)
+		}
+		eq = conv.equality(xForm, yForm, typ)
+	}
+
+	if node.Op == token.NEQ {
+		return sexp.NewNot(eq)
+	}
+	return eq
+}
+
+// equality returns form that reports whether x and y are equal.
+// Both operands have the specified type.
+func (conv *converter) equality(x, y sexp.Form, typ types.Type) sexp.Form {
+	if isLispType(typ) {
+		return sexp.NewLispCall(lisp.FnEqual, x, y)
+	}
+
+	switch utyp := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case utyp.Info()&types.IsNumeric != 0:
+			return sexp.NewNumEq(x, y)
+		case utyp.Info()&types.IsString != 0:
+			return sexp.NewStrEq(x, y)
+		default:
+			return sexp.NewLispCall(lisp.FnEq, x, y)
+		}
+
+	case *types.Pointer:
+		if isRefPointee(utyp.Elem()) {
+			return sexp.NewLispCall(lisp.FnEq, x, y)
+		}
+		return sexp.NewCall(rt.FnPtrEq, x, y)
+
+	case *types.Interface:
+		if utyp.Empty() {
+			return sexp.NewCall(rt.FnObjectEq, x, y)
+		}
+		return sexp.NewCall(rt.FnIfaceEq, x, y)
+
+	case *types.Struct, *types.Array:
+		if equalSafe(typ) {
+			return sexp.NewLispCall(lisp.FnEqual, x, y)
+		}
+		return conv.elemsEquality(x, y, utyp)
+
+	default: // Channels
+		return sexp.NewLispCall(lisp.FnEq, x, y)
+	}
+}
+
+// elemsEquality compares structs and arrays element-wise.
+// Blank struct fields are ignored.
+func (conv *converter) elemsEquality(x, y sexp.Form, typ types.Type) sexp.Form {
+	// Operands are referenced for every element,
+	// so they are evaluated only once.
+	env := &tmpEnv{}
+	x, y = env.bind(x), env.bind(y)
+
+	var conds []sexp.Form
+	switch typ := typ.(type) {
+	case *types.Struct:
+		for i := 0; i < typ.NumFields(); i++ {
+			field := typ.Field(i)
+			if field.Name() == blankIdent {
+				continue
+			}
+			conds = append(conds, conv.equality(
+				&sexp.StructIndex{Struct: x, Index: i, Typ: typ},
+				&sexp.StructIndex{Struct: y, Index: i, Typ: typ},
+				field.Type(),
+			))
+		}
+	case *types.Array:
+		for i := 0; i < int(typ.Len()); i++ {
+			conds = append(conds, conv.equality(
+				&sexp.ArrayIndex{Array: x, Index: sexp.Int(i)},
+				&sexp.ArrayIndex{Array: y, Index: sexp.Int(i)},
+				typ.Elem(),
+			))
+		}
+	}
+	var eq sexp.Form = sexp.Bool(true)
+	if len(conds) != 0 {
+		eq = conds[0]
+		for _, cond := range conds[1:] {
+			eq = &sexp.And{X: eq, Y: cond}
+		}
+	}
+
+	if len(env.forms) == 0 {
+		return eq
+	}
+	binds := make([]*sexp.Bind, len(env.forms))
+	for i, form := range env.forms {
+		binds[i] = form.(*sexp.Bind)
+	}
+	return &sexp.Let{Bindings: binds, Expr: eq}
+}
+
+// equalSafe reports whether Go "==" for values of given
+// type can be implemented by Emacs Lisp "equal".
+// Floats ("0.0 == -0.0"), pointers and interfaces are not safe.
+func equalSafe(typ types.Type) bool {
+	if isLispType(typ) {
+		return true
+	}
+	switch typ := typ.Underlying().(type) {
+	case *types.Basic:
+		return typ.Info()&(types.IsInteger|types.IsBoolean|types.IsString) != 0
+	case *types.Struct:
+		for i := 0; i < typ.NumFields(); i++ {
+			if !equalSafe(typ.Field(i).Type()) {
+				return false
+			}
+		}
+		return true
+	case *types.Array:
+		return equalSafe(typ.Elem())
+	default:
+		return false
+	}
+}
+
+// mapKeyTest returns hash table test for map keys of given type.
+// Struct and array keys that are not equalSafe use
+// generated test, see keyTest.
+func (conv *converter) mapKeyTest(typ types.Type) sexp.Form {
+	if equalSafe(typ) {
+		return sexp.Symbol{Val: "equal"}
+	}
+	switch utyp := typ.Underlying().(type) {
+	case *types.Basic:
+		return rtKeyTest("FloatKeyTest")
+	case *types.Pointer:
+		if isRefPointee(utyp.Elem()) {
+			return sexp.Symbol{Val: "eq"}
+		}
+		return rtKeyTest("PtrKeyTest")
+	case *types.Chan:
+		return sexp.Symbol{Val: "eq"}
+	case *types.Interface:
+		if utyp.Empty() {
+			return rtKeyTest("ObjectKeyTest")
+		}
+		return rtKeyTest("IfaceKeyTest")
+	case *types.Struct, *types.Array:
+		return conv.keyTest(typ)
+	default:
+		return sexp.Symbol{Val: "equal"}
+	}
+}
+
+// keyTest returns hash table test for struct or array keys.
+// Test functions are generated once per package and key type;
+// they are collected along with function literals.
+// Test compares keys like "==" does; hash combines
+// element hashes of their own key tests.
+func (conv *converter) keyTest(typ types.Type) sexp.Form {
+	name := symbols.ManglePriv(conv.pkg.FullName, "%keytest/"+types.TypeString(typ, nil))
+	fns, ok := conv.keyTests[name]
+	if !ok {
+		x := types.NewVar(token.NoPos, nil, "x", typ)
+		y := types.NewVar(token.NoPos, nil, "y", typ)
+		eqSig := types.NewSignatureType(nil, nil, nil,
+			types.NewTuple(x, y),
+			types.NewTuple(types.NewVar(token.NoPos, nil, "", types.Typ[types.Bool])),
+			false)
+		hashSig := types.NewSignatureType(nil, nil, nil,
+			types.NewTuple(x),
+			types.NewTuple(types.NewVar(token.NoPos, nil, "", lisp.TypObject)),
+			false)
+		fns = [2]*sexp.Lambda{
+			{Fn: &sexp.Func{Name: name + "/eq", Params: []string{"x", "y"}, Results: eqSig.Results()}, Typ: eqSig},
+			{Fn: &sexp.Func{Name: name + "/hash", Params: []string{"x"}, Results: hashSig.Results()}, Typ: hashSig},
+		}
+		// Registered before bodies are converted;
+		// nested keys may refer to the same test.
+		conv.keyTests[name] = fns
+		xForm := sexp.Local{Name: "x", Typ: typ}
+		yForm := sexp.Local{Name: "y", Typ: typ}
+		fns[0].Fn.Body = sexp.Block{&sexp.Return{
+			Results: []sexp.Form{conv.equality(xForm, yForm, typ)},
+		}}
+		fns[1].Fn.Body = sexp.Block{&sexp.Return{
+			Results: []sexp.Form{conv.keyHash(xForm, typ)},
+		}}
+		conv.lambdas.funcs = append(conv.lambdas.funcs, fns[0].Fn, fns[1].Fn)
+	}
+	return sexp.NewCall(rt.FnKeyTest, sexp.Symbol{Val: name}, fns[0], fns[1])
+}
+
+// keyHash returns hash of struct or array key x.
+// Elements that can be compared by "equal" are hashed as is.
+func (conv *converter) keyHash(x sexp.Form, typ types.Type) sexp.Form {
+	var elems []sexp.Form
+	switch utyp := typ.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < utyp.NumFields(); i++ {
+			field := utyp.Field(i)
+			if field.Name() == blankIdent {
+				continue
+			}
+			elem := &sexp.StructIndex{Struct: x, Index: i, Typ: utyp}
+			elems = append(elems, conv.elemHash(elem, field.Type()))
+		}
+	case *types.Array:
+		for i := 0; i < int(utyp.Len()); i++ {
+			elem := &sexp.ArrayIndex{Array: x, Index: sexp.Int(i)}
+			elems = append(elems, conv.elemHash(elem, utyp.Elem()))
+		}
+	}
+	return sexp.NewCall(
+		rt.FnKeyHash,
+		sexp.Symbol{Val: "equal"},
+		sexp.NewLispCall(lisp.FnList, elems...),
+	)
+}
+
+func (conv *converter) elemHash(elem sexp.Form, typ types.Type) sexp.Form {
+	if equalSafe(typ) {
+		return elem
+	}
+	return sexp.NewCall(rt.FnKeyHash, conv.mapKeyTest(typ), elem)
+}
+
+// rtKeyTest returns hash table test that is defined by runtime.
+func rtKeyTest(name string) sexp.Form {
+	return sexp.Var{Name: "goism-rt." + name, Typ: lisp.TypObject}
+}
+
+func isFloat(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsFloat != 0
+}
+
+func isNumeric(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsNumeric != 0
+}
+
+// isLispType reports whether typ is lisp.Object or lisp.Symbol.
+func isLispType(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	return ok && named.Obj().Pkg() == lisp.Package
+}
@@ -36,7 +36,15 @@ func (conv *converter) Expr(node ast.Expr) sexp.Form {
 	case *ast.TypeAssertExpr:
 		return conv.TypeAssertExpr(node)
 	case *ast.IndexExpr:
+		if x := conv.instantiatedFunc(node); x != nil {
+			return conv.Expr(x)
+		}
 		return conv.IndexExpr(node)
+	case *ast.IndexListExpr:
+		if x := conv.instantiatedFunc(node); x != nil {
+			return conv.Expr(x)
+		}
+		panic(errUnexpectedExpr(conv, node))
 	case *ast.UnaryExpr:
 		return conv.UnaryExpr(node)
 	case *ast.CompositeLit:
@@ -45,6 +53,8 @@ func (conv *converter) Expr(node ast.Expr) sexp.Form {
 		return conv.SliceExpr(node)
 	case *ast.StarExpr:
 		return conv.StarExpr(node)
+	case *ast.FuncLit:
+		return conv.FuncLit(node)
 
 	default:
 		panic(errUnexpectedExpr(conv, node))
@@ -62,26 +72,25 @@ func (conv *converter) Ident(node *ast.Ident) sexp.Form {
 	if typ, ok := typ.(*types.Basic); ok {
 		// Coerce untyped nil to correct value depending on
 		// the context type.
-		if typ.Kind() == types.UntypedNil {
-			switch conv.ctxType.(type) {
-			case *types.Map:
-				return nilMap
-			case *types.Slice:
-				return nilSlice
-			case *types.Signature:
-				return nilFunc
-			case *types.Interface:
-				return nilInterface
+		if typ.Kind() == types.UntypedNil && conv.ctxType != nil {
+			if nv := nilValue(conv.ctxType); nv != nil {
+				return nv
 			}
 		}
 	}
 
+	if _, ok := obj.(*types.Func); ok {
+		return conv.funcValue(node)
+	}
 	if xtypes.IsGlobal(obj) {
 		return sexp.Var{
 			Name: conv.env.InternVar(nil, node.Name),
 			Typ:  typ,
 		}
 	}
+	if conv.isBoxed(obj) {
+		return unbox(node.Name, typ)
+	}
 	return sexp.Local{
 		Name: node.Name,
 		Typ:  typ,
@@ -93,19 +102,20 @@ func (conv *converter) BinaryExpr(node *ast.BinaryExpr) sexp.Form {
 		return cv
 	}
 
+	if node.Op == token.EQL || node.Op == token.NEQ {
+		if form := conv.comparison(node); form != nil {
+			return form
+		}
+	}
+
 	typ := conv.basicTypeOf(node.X)
 	x, y := conv.Expr(node.X), conv.Expr(node.Y)
 
 	if typ.Info()&types.IsNumeric != 0 {
+		if form := conv.arith(node.Op, conv.typeOf(node), x, y); form != nil {
+			return form
+		}
 		switch node.Op {
-		case token.ADD:
-			return sexp.NewAdd(x, y)
-		case token.SUB:
-			return sexp.NewSub(x, y)
-		case token.MUL:
-			return sexp.NewMul(x, y)
-		case token.QUO:
-			return sexp.NewQuo(x, y)
 		case token.EQL:
 			return sexp.NewNumEq(x, y)
 		case token.NEQ:
@@ -118,14 +128,6 @@ func (conv *converter) BinaryExpr(node *ast.BinaryExpr) sexp.Form {
 			return sexp.NewNumLte(x, y)
 		case token.GEQ:
 			return sexp.NewNumGte(x, y)
-		case token.AND:
-			return sexp.NewBitAnd(x, y)
-		case token.OR:
-			return sexp.NewBitOr(x, y)
-		case token.SHL:
-			return sexp.NewShl(x, y)
-		case token.SHR:
-			return sexp.NewShr(x, y)
 
 		default:
 			panic(errUnexpectedExpr(conv, node))
@@ -164,12 +166,73 @@ func (conv *converter) BinaryExpr(node *ast.BinaryExpr) sexp.Form {
 	panic(errUnexpectedExpr(conv, node))
 }
 
-func (conv *converter) structIndex(typ *types.Struct, node *ast.SelectorExpr) *sexp.StructIndex {
-	return &sexp.StructIndex{
-		Struct: conv.Expr(node.X),
-		Index:  xtypes.LookupField(node.Sel.Name, typ),
-		Typ:    typ,
+// arithOp returns arithmetic or bitwise operation form.
+// Returns nil if op is not arithmetic.
+//
+// Emacs "/" and "%" truncate towards zero, just like Go does.
+// Integer division by zero is checked by arith.
+func arithOp(op token.Token, x, y sexp.Form) sexp.Form {
+	switch op {
+	case token.ADD:
+		return sexp.NewAdd(x, y)
+	case token.SUB:
+		return sexp.NewSub(x, y)
+	case token.MUL:
+		return sexp.NewMul(x, y)
+	case token.QUO:
+		return sexp.NewQuo(x, y)
+	case token.REM:
+		return sexp.NewRem(x, y)
+	case token.AND:
+		return sexp.NewBitAnd(x, y)
+	case token.OR:
+		return sexp.NewBitOr(x, y)
+	case token.XOR:
+		return sexp.NewBitXor(x, y)
+	case token.AND_NOT:
+		return sexp.NewAndNot(x, y)
+	case token.SHL:
+		return sexp.NewShl(x, y)
+	case token.SHR:
+		return sexp.NewShr(x, y)
+
+	default:
+		return nil
+	}
+}
+
+// bitNot = "^x".
+// Unsigned values must stay non-negative, so they
+// are inverted by xor with all bits mask.
+// 64-bit unsigned results are truncated by uintElem,
+// or by wrapInt if package is in wraparound mode.
+func (conv *converter) bitNot(typ *types.Basic, x sexp.Form) sexp.Form {
+	switch typ.Kind() {
+	case types.Uint8:
+		return sexp.NewBitXor(x, sexp.Int(0xFF))
+	case types.Uint16:
+		return sexp.NewBitXor(x, sexp.Int(0xFFFF))
+	case types.Uint32:
+		return sexp.NewBitXor(x, sexp.Int(0xFFFFFFFF))
+	case types.Uint64, types.Uint, types.Uintptr:
+		return conv.uintElem(sexp.NewBitNot(x), typ)
+	default:
+		return sexp.NewBitNot(x)
+	}
+}
+
+// selectPath returns form that selects embedded field specified by
+// path (field index for each level of embedding) along with its type.
+// Single level of indirection for structs is the same
+// as not having indirection at all, so embedded pointers
+// are traversed in the same way.
+func selectPath(x sexp.Form, typ types.Type, path []int) (sexp.Form, types.Type) {
+	for _, i := range path {
+		structTyp := xtypes.MaybeDeref(typ).Underlying().(*types.Struct)
+		x = &sexp.StructIndex{Struct: x, Index: i, Typ: structTyp}
+		typ = structTyp.Field(i).Type()
 	}
+	return x, typ
 }
 
 func (conv *converter) SelectorExpr(node *ast.SelectorExpr) sexp.Form {
@@ -177,15 +240,20 @@ func (conv *converter) SelectorExpr(node *ast.SelectorExpr) sexp.Form {
 		return cv
 	}
 
-	typ := conv.typeOf(node.X)
-	if typ, ok := typ.Underlying().(*types.Struct); ok {
-		return conv.structIndex(typ, node)
+	if obj, ok := conv.info.Uses[node.Sel].(*types.Func); ok {
+		if obj.Type().(*types.Signature).Recv() == nil {
+			return conv.funcValue(node.Sel)
+		}
 	}
-	if typ, ok := typ.(*types.Pointer); ok {
-		// Single level of indirection for structs is the same
-		// as not having indirection at all.
-		if derefTyp, ok := typ.Elem().Underlying().(*types.Struct); ok {
-			return conv.structIndex(derefTyp, node)
+	if sel := conv.info.Selections[node]; sel != nil {
+		switch sel.Kind() {
+		case types.FieldVal:
+			form, _ := selectPath(conv.Expr(node.X), conv.typeOf(node.X), sel.Index())
+			return form
+		case types.MethodVal:
+			return conv.methodValue(node, sel)
+		case types.MethodExpr:
+			return conv.methodExpr(node, sel)
 		}
 	}
 
@@ -195,8 +263,9 @@ func (conv *converter) SelectorExpr(node *ast.SelectorExpr) sexp.Form {
 	}
 	switch obj := conv.info.Uses[id].(type) {
 	case *types.PkgName:
-		return sexp.Symbol{
-			Val: conv.env.InternVar(obj.Imported(), node.Sel.Name),
+		return sexp.Var{
+			Name: conv.env.InternVar(obj.Imported(), node.Sel.Name),
+			Typ:  conv.typeOf(node),
 		}
 	default:
 		panic(errUnexpectedExpr(conv, node))
@@ -208,24 +277,30 @@ func (conv *converter) UnaryExpr(node *ast.UnaryExpr) sexp.Form {
 		return cv
 	}
 
+	if node.Op == token.AND {
+		return conv.addrOf(node.X)
+	}
+
 	x := conv.Expr(node.X)
 
 	switch node.Op {
 	case token.NOT:
 		return sexp.NewNot(x)
 	case token.SUB:
-		return sexp.NewNeg(x)
+		return conv.wrapInt(sexp.NewNeg(x), conv.typeOf(node))
 	case token.ADD:
 		return x
-	case token.AND:
-		return conv.takeAddr(x)
+	case token.XOR:
+		return conv.wrapInt(conv.bitNot(conv.basicTypeOf(node.X), x), conv.typeOf(node))
+	case token.ARROW:
+		return conv.chanRecv(x)
 	}
 
 	panic(errUnexpectedExpr(conv, node))
 }
 
 func (conv *converter) takeAddr(form sexp.Form) sexp.Form {
-	if typ := form.Type(); xtypes.IsStruct(typ) {
+	if typ := form.Type(); isRefPointee(typ) {
 		return &sexp.TypeCast{
 			Form: form,
 			Typ:  types.NewPointer(typ),
@@ -235,18 +310,27 @@ func (conv *converter) takeAddr(form sexp.Form) sexp.Form {
 	panic(exn.NoImpl("take address operation"))
 }
 
-func (conv *converter) TypeAssertExpr(node *ast.TypeAssertExpr) sexp.Form {
-	expr := conv.Expr(node.X)
-	assertTyp := conv.typeOf(node.Type)
-	return &sexp.TypeAssert{Expr: expr, Typ: assertTyp}
+// mapIndexOk = "val, ok := m[key]".
+// Returned call packs both results.
+func (conv *converter) mapIndexOk(node *ast.IndexExpr) (sexp.Form, *types.Tuple) {
+	typ := conv.typeOf(node.X).Underlying().(*types.Map)
+	get := conv.call(rt.FnMapGetOk, node.X, conv.mapKey(node.Index, typ), ZeroValue(typ.Elem()))
+	return get, okTuple(typ.Elem())
+}
+
+// mapKey converts map index expression key.
+// Key is converted to the map key type (for example, to interface).
+func (conv *converter) mapKey(node ast.Expr, typ *types.Map) sexp.Form {
+	conv.ctxType = typ.Key()
+	return conv.copyValue(conv.Expr(node), typ.Key())
 }
 
 func (conv *converter) IndexExpr(node *ast.IndexExpr) sexp.Form {
-	switch typ := conv.typeOf(node.X).(type) {
+	switch typ := conv.typeOf(node.X).Underlying().(type) {
 	case *types.Map:
 		return conv.lispCall(
 			lisp.FnGethash,
-			node.Index,
+			conv.mapKey(node.Index, typ),
 			node.X,
 			ZeroValue(typ.Elem()),
 		)
@@ -257,6 +341,13 @@ func (conv *converter) IndexExpr(node *ast.IndexExpr) sexp.Form {
 			Index: conv.Expr(node.Index),
 		}
 
+	case *types.Pointer:
+		// Pointer to array is the array itself.
+		return &sexp.ArrayIndex{
+			Array: conv.Expr(node.X),
+			Index: conv.Expr(node.Index),
+		}
+
 	case *types.Slice:
 		return conv.call(rt.FnSliceGet, node.X, node.Index)
 
@@ -270,30 +361,70 @@ func (conv *converter) IndexExpr(node *ast.IndexExpr) sexp.Form {
 }
 
 func (conv *converter) SliceExpr(node *ast.SliceExpr) sexp.Form {
-	low := conv.ExprOrNil(node.Low)
-	high := conv.ExprOrNil(node.High)
 	x := conv.Expr(node.X)
-	switch conv.typeOf(node.X).Underlying().(type) {
-	case *types.Array:
+	if _, ok := conv.typeOf(node.X).Underlying().(*types.Basic); ok {
+		return sexp.NewSubstr(x, conv.ExprOrNil(node.Low), conv.ExprOrNil(node.High))
+	}
+	// Missing bounds are left nil; span kind depends on them.
+	var low, high sexp.Form
+	if node.Low != nil {
+		low = conv.Expr(node.Low)
+	}
+	if node.High != nil {
+		high = conv.Expr(node.High)
+	}
+	if _, ok := conv.typeOf(node.X).Underlying().(*types.Array); ok {
 		return sexp.NewArraySlice(x, low, high)
-	case *types.Basic:
-		return sexp.NewSubstr(x, low, high)
-	default:
-		return sexp.NewSubslice(x, low, high)
 	}
+	return sexp.NewSubslice(x, low, high)
 }
 
 func (conv *converter) CompositeLit(node *ast.CompositeLit) sexp.Form {
-	switch typ := conv.typeOf(node).(type) {
+	typ := conv.typeOf(node)
+	form := conv.compositeLit(node, typ)
+	if isTaggedType(typ) && !types.Identical(form.Type(), typ) {
+		// Keep named type, so its dynamic type tag is not lost.
+		return &sexp.TypeCast{Form: form, Typ: typ}
+	}
+	return form
+}
+
+func (conv *converter) compositeLit(node *ast.CompositeLit, typ types.Type) sexp.Form {
+	switch utyp := typ.Underlying().(type) {
 	case *types.Array:
-		return conv.arrayLit(node, typ)
+		return conv.arrayLit(node, utyp)
 	case *types.Slice:
-		return conv.sliceLit(node, typ)
-	case *types.Named:
-		return conv.structLit(node, typ)
+		return conv.sliceLit(node, utyp)
+	case *types.Map:
+		return conv.mapLit(node, utyp)
+	case *types.Struct:
+		if typ, ok := typ.(*types.Named); ok {
+			return conv.structLit(node, typ)
+		}
+	case *types.Pointer:
+		// Elided "&T" inside composite literal.
+		return conv.takeAddr(conv.compositeLit(node, utyp.Elem()))
+	}
+	panic(errUnexpectedExpr(conv, node))
+}
 
-	default:
-		panic(errUnexpectedExpr(conv, node))
+// mapLit = "map[K]V{k1: v1, ...}".
+// Keys and values are evaluated in order of appearance.
+func (conv *converter) mapLit(node *ast.CompositeLit, typ *types.Map) sexp.Form {
+	test := conv.mapKeyTest(typ.Key())
+	if len(node.Elts) == 0 {
+		return conv.call(rt.FnMakeMap, test)
+	}
+	kvs := make([]sexp.Form, 0, len(node.Elts)*2)
+	for _, elt := range node.Elts {
+		elt := elt.(*ast.KeyValueExpr)
+		conv.ctxType = typ.Elem()
+		val := conv.copyValue(conv.Expr(elt.Value), typ.Elem())
+		kvs = append(kvs, conv.mapKey(elt.Key, typ), val)
+	}
+	return &sexp.TypeCast{
+		Form: conv.call(rt.FnMapLit, test, sexp.NewLispCall(lisp.FnList, kvs...)),
+		Typ:  typ,
 	}
 }
 
@@ -341,11 +472,16 @@ func (conv *converter) arrayLit(node *ast.CompositeLit, typ *types.Array) sexp.F
 func (conv *converter) structLit(node *ast.CompositeLit, typ *types.Named) sexp.Form {
 	structTyp := typ.Underlying().(*types.Struct)
 	vals := make([]sexp.Form, structTyp.NumFields())
-	for _, elt := range node.Elts {
-		kv := elt.(*ast.KeyValueExpr)
-		key := kv.Key.(*ast.Ident)
-		idx := xtypes.LookupField(key.Name, structTyp)
-		vals[idx] = conv.copyValue(conv.Expr(kv.Value), structTyp.Field(idx).Type())
+	for i, elt := range node.Elts {
+		// Elements are either all keyed or all positional.
+		idx := i
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			idx = xtypes.LookupField(kv.Key.(*ast.Ident).Name, structTyp)
+			elt = kv.Value
+		}
+		fieldTyp := structTyp.Field(idx).Type()
+		conv.ctxType = fieldTyp
+		vals[idx] = conv.copyValue(conv.Expr(elt), fieldTyp)
 	}
 	for i, val := range vals {
 		if val == nil {
@@ -356,9 +492,5 @@ func (conv *converter) structLit(node *ast.CompositeLit, typ *types.Named) sexp.
 }
 
 func (conv *converter) StarExpr(node *ast.StarExpr) sexp.Form {
-	typ := conv.typeOf(node.X).(*types.Pointer)
-	if derefTyp, ok := typ.Elem().(*types.Named); ok {
-		return conv.copyNamed(derefTyp, conv.Expr(node.X))
-	}
-	panic(errUnexpectedExpr(conv, node))
+	return conv.deref(node)
 }
@@ -1,52 +1,233 @@
 package sexpconv
 
 import (
+	"assert"
 	"exn"
 	"go/ast"
-	"go/token"
 	"go/types"
+	"magic_pkg/emacs/lisp"
+	"magic_pkg/emacs/rt"
 	"sexp"
 )
 
+// Locals that hold range statement iteration state.
+const (
+	rangeExpr   = "_rx" // Range expression value
+	rangeIndex  = "_ri" // Current index
+	rangeLen    = "_rn" // Number of iterations
+	rangeOffset = "_ro" // String byte offset
+	rangeMap    = "_rm" // Map that is being iterated
+	rangeVal    = "_rv" // Current map value
+)
+
 func (conv *converter) RangeStmt(node *ast.RangeStmt) sexp.Form {
-	switch typ := conv.typeOf(node.X).(type) {
+	conv.pushTarget(true)
+	return conv.popTarget(conv.rangeStmt(node))
+}
+
+func (conv *converter) rangeStmt(node *ast.RangeStmt) sexp.Form {
+	if call, ok := node.X.(*ast.CallExpr); ok && isLispSeqCall(conv, call) {
+		return conv.foreachSeq(node, conv.Expr(call.Args[0]))
+	}
+
+	switch typ := conv.typeOf(node.X).Underlying().(type) {
 	case *types.Array:
 		return conv.foreachArray(node, typ)
+	case *types.Slice:
+		return conv.foreachSlice(node, typ)
+	case *types.Basic:
+		assert.True(typ.Info()&types.IsString != 0)
+		return conv.foreachString(node)
+	case *types.Map:
+		return conv.foreachMap(node)
+	case *types.Chan:
+		return conv.foreachChan(node)
 
 	default:
 		panic(exn.NoImpl("for/range for %T", typ))
 	}
 }
 
-func (conv *converter) foreachArray(node *ast.RangeStmt, typ *types.Array) sexp.Form {
-	if node.Tok == token.ASSIGN {
-		panic(exn.NoImpl("'=' assign in for initializer"))
+// rangeLoop creates loop that assigns key and val forms
+// to range statement iteration variables before each body execution.
+func (conv *converter) rangeLoop(node *ast.RangeStmt, init []sexp.Form, cond, key, val, post sexp.Form) sexp.Form {
+	return &sexp.While{
+		Init: sexp.FormList(init),
+		Cond: cond,
+		Post: post,
+		Body: conv.rangeBody(node, key, val),
 	}
+}
 
-	body := conv.BlockStmt(node.Body)
+// rangeBody assigns key and val forms to range statement
+// iteration variables and executes loop body.
+func (conv *converter) rangeBody(node *ast.RangeStmt, key, val sexp.Form) sexp.Block {
+	body := make([]sexp.Form, 0, 3)
+	if node.Key != nil && !isBlankIdent(node.Key) {
+		body = append(body, conv.assign(node.Key, key))
+	}
+	if node.Value != nil && !isBlankIdent(node.Value) {
+		body = append(body, conv.assign(node.Value, val))
+	}
+	return append(body, conv.loopBody(node.Body))
+}
+
+// indexLoop is rangeLoop that iterates over [0, n) indexes.
+func (conv *converter) indexLoop(node *ast.RangeStmt, init []sexp.Form, n, val sexp.Form) sexp.Form {
+	index := sexp.Local{Name: rangeIndex, Typ: types.Typ[types.Int]}
+	init = append(init,
+		&sexp.Bind{Name: rangeLen, Init: n},
+		&sexp.Bind{Name: rangeIndex, Init: sexp.Int(0)},
+	)
+	return conv.rangeLoop(
+		node,
+		init,
+		sexp.NewNumLt(index, sexp.Local{Name: rangeLen, Typ: types.Typ[types.Int]}),
+		index,
+		val,
+		&sexp.Rebind{Name: rangeIndex, Expr: sexp.NewAdd1(index)},
+	)
+}
 
+func (conv *converter) foreachArray(node *ast.RangeStmt, typ *types.Array) sexp.Form {
 	// for range <X>.
-	if node.Value == nil && node.Key == nil {
-		return &sexp.Repeat{N: typ.Len(), Body: body}
-	}
-	key := node.Key.(*ast.Ident)
-	keyTyp := conv.basicTypeOf(key)
-
-	// for <KEY> := range <X>.
-	if node.Value == nil {
-		return &sexp.DoTimes{
-			N:    sexp.Int(typ.Len()),
-			Iter: sexp.Local{Name: key.Name, Typ: keyTyp},
-			Step: sexp.Int(1),
-			Body: body,
+	if node.Value == nil && node.Key == nil && !hasBranches(node.Body) {
+		return &sexp.Repeat{N: typ.Len(), Body: conv.BlockStmt(node.Body)}
+	}
+
+	arr := sexp.Local{Name: rangeExpr, Typ: typ}
+	var init []sexp.Form
+	var val sexp.Form
+	if node.Value != nil {
+		// Range expression is evaluated once and iterated over
+		// its copy, so body stores into array are not observed.
+		init = append(init, &sexp.Bind{Name: rangeExpr, Init: conv.copyValue(conv.Expr(node.X), nil)})
+		val = &sexp.ArrayIndex{
+			Array: arr,
+			Index: sexp.Local{Name: rangeIndex, Typ: types.Typ[types.Int]},
 		}
 	}
+	return conv.indexLoop(node, init, sexp.Int(typ.Len()), val)
+}
 
-	// For <KEY>, <VAL> := range <X>.
-	panic(exn.NoImpl("for loop variant"))
+func (conv *converter) foreachSlice(node *ast.RangeStmt, typ *types.Slice) sexp.Form {
+	slice := sexp.Local{Name: rangeExpr, Typ: conv.typeOf(node.X)}
+	init := []sexp.Form{
+		&sexp.Bind{Name: rangeExpr, Init: conv.Expr(node.X)},
+	}
+	val := &sexp.TypeCast{
+		Form: conv.call(rt.FnSliceGet, slice, sexp.Local{Name: rangeIndex, Typ: types.Typ[types.Int]}),
+		Typ:  typ.Elem(),
+	}
+	return conv.indexLoop(node, init, conv.call(rt.FnSliceLen, slice), val)
+}
+
+// foreachString iterates over string runes.
+// Keys are byte offsets of UTF-8 encoded runes.
+func (conv *converter) foreachString(node *ast.RangeStmt) sexp.Form {
+	intTyp := types.Typ[types.Int]
+	str := sexp.Local{Name: rangeExpr, Typ: types.Typ[types.String]}
+	index := sexp.Local{Name: rangeIndex, Typ: intTyp}
+	offset := sexp.Local{Name: rangeOffset, Typ: intTyp}
+	init := []sexp.Form{
+		&sexp.Bind{Name: rangeExpr, Init: conv.Expr(node.X)},
+		&sexp.Bind{Name: rangeLen, Init: sexp.NewLispCall(lisp.FnLen, str)},
+		&sexp.Bind{Name: rangeIndex, Init: sexp.Int(0)},
+		&sexp.Bind{Name: rangeOffset, Init: sexp.Int(0)},
+	}
+	post := sexp.FormList{
+		&sexp.Rebind{
+			Name: rangeOffset,
+			Expr: sexp.NewAdd(offset, conv.call(rt.FnStringRuneWidth, str, index)),
+		},
+		&sexp.Rebind{Name: rangeIndex, Expr: sexp.NewAdd1(index)},
+	}
+	val := &sexp.TypeCast{
+		Form: sexp.NewLispCall(lisp.FnAref, str, index),
+		Typ:  types.Typ[types.Rune],
+	}
+	return conv.rangeLoop(
+		node,
+		init,
+		sexp.NewNumLt(index, sexp.Local{Name: rangeLen, Typ: intTyp}),
+		offset,
+		val,
+		post,
+	)
+}
+
+// foreachMap iterates over map keys that are collected
+// before the first iteration.
+// Values are looked up during iteration, so entries that
+// are deleted before they are reached are skipped.
+func (conv *converter) foreachMap(node *ast.RangeStmt) sexp.Form {
+	typ := conv.typeOf(node.X).Underlying().(*types.Map)
+	m := sexp.Local{Name: rangeMap, Typ: typ}
+	cell := sexp.Local{Name: rangeExpr, Typ: lisp.TypObject}
+	val := sexp.Local{Name: rangeVal, Typ: lisp.TypObject}
+	key := sexp.NewLispCall(lisp.FnCar, cell)
+	missing := sexp.Var{Name: "goism-rt.MapMissing", Typ: lisp.TypObject}
+	return &sexp.While{
+		Init: sexp.FormList{
+			&sexp.Bind{Name: rangeMap, Init: conv.Expr(node.X)},
+			&sexp.Bind{Name: rangeExpr, Init: sexp.NewCall(rt.FnMapKeys, m)},
+		},
+		Cond: cell,
+		Post: &sexp.Rebind{Name: rangeExpr, Expr: sexp.NewLispCall(lisp.FnCdr, cell)},
+		Body: sexp.Block{
+			&sexp.Bind{Name: rangeVal, Init: sexp.NewLispCall(lisp.FnGethash, key, m, missing)},
+			&sexp.If{
+				Cond: sexp.NewNot(sexp.NewLispCall(lisp.FnEq, val, missing)),
+				Then: conv.rangeBody(
+					node,
+					&sexp.TypeCast{Form: key, Typ: typ.Key()},
+					&sexp.TypeCast{Form: val, Typ: typ.Elem()},
+				),
+				Else: sexp.EmptyForm,
+			},
+		},
+	}
+}
+
+// foreachSeq iterates over Emacs Lisp list or vector.
+func (conv *converter) foreachSeq(node *ast.RangeStmt, seq sexp.Form) sexp.Form {
+	index := sexp.Local{Name: rangeIndex, Typ: types.Typ[types.Int]}
+	elem := sexp.NewLispCall(lisp.FnCar, sexp.Local{Name: rangeExpr, Typ: lisp.TypObject})
+	return conv.listLoop(node, conv.call(rt.FnSeqList, seq), index, elem)
+}
+
+// listLoop is rangeLoop that traverses Emacs Lisp list.
+// Current list cell is bound to rangeExpr.
+func (conv *converter) listLoop(node *ast.RangeStmt, list, key, val sexp.Form) sexp.Form {
+	cell := sexp.Local{Name: rangeExpr, Typ: lisp.TypObject}
+	index := sexp.Local{Name: rangeIndex, Typ: types.Typ[types.Int]}
+	init := []sexp.Form{
+		&sexp.Bind{Name: rangeExpr, Init: list},
+		&sexp.Bind{Name: rangeIndex, Init: sexp.Int(0)},
+	}
+	post := sexp.FormList{
+		&sexp.Rebind{Name: rangeExpr, Expr: sexp.NewLispCall(lisp.FnCdr, cell)},
+		&sexp.Rebind{Name: rangeIndex, Expr: sexp.NewAdd1(index)},
+	}
+	return conv.rangeLoop(node, init, cell, key, val, post)
+}
+
+// isLispSeqCall reports whether node is "lisp.Seq(x)" call.
+func isLispSeqCall(conv *converter, node *ast.CallExpr) bool {
+	sel, ok := node.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := conv.info.Uses[sel.Sel].(*types.Func)
+	return ok && fn.Pkg() == lisp.Package && fn.Name() == "Seq"
 }
 
 func (conv *converter) ForStmt(node *ast.ForStmt) sexp.Form {
+	conv.pushTarget(true)
+	return conv.popTarget(conv.forStmt(node))
+}
+
+func (conv *converter) forStmt(node *ast.ForStmt) sexp.Form {
 	var (
 		post sexp.Form
 		init sexp.Form
@@ -63,7 +244,7 @@ func (conv *converter) ForStmt(node *ast.ForStmt) sexp.Form {
 		init = conv.Stmt(node.Init)
 	}
 
-	body := conv.BlockStmt(node.Body)
+	body := conv.loopBody(node.Body)
 
 	if node.Cond == nil {
 		return &sexp.Loop{
@@ -0,0 +1,407 @@
+package sexpconv
+
+import (
+	"exn"
+	"go/ast"
+	"go/types"
+	"sexp"
+	"strings"
+	"tu/symbols"
+	"xtypes"
+)
+
+// Generic functions and methods of generic types are
+// monomorphized: every used combination of type arguments
+// gets its own function that is converted with type
+// parameters replaced by type arguments.
+//
+// Instance names are derived from generic function name
+// and type arguments, so instances that are created by
+// different packages have the same name and definition.
+
+// Instance is a generic function instance that needs a body.
+type Instance struct {
+	Fn       *sexp.Func
+	Generic  string // Symbol of generic function
+	TypeArgs []types.Type
+}
+
+// instanceEnv collects generic function instances.
+type instanceEnv struct {
+	funcs map[string]*sexp.Func
+	queue []*Instance
+	// Shared by all substitutions, so equal
+	// instances of generic types are deduplicated.
+	ctxt *types.Context
+}
+
+// TakeInstances returns generic function instances that were
+// created since the last TakeInstances call.
+// Instance bodies can create new instances, so it should
+// be called until it returns empty slice.
+func (conv *Converter) TakeInstances() []*Instance {
+	insts := conv.instances.queue
+	conv.instances.queue = nil
+	return insts
+}
+
+// instance returns generic function instance,
+// creating it if needed.
+func (conv *converter) instance(generic, name string, sig *types.Signature, targs []types.Type) *sexp.Func {
+	if fn := conv.instances.funcs[name]; fn != nil {
+		return fn
+	}
+	fn := &sexp.Func{Name: name, Results: sig.Results()}
+	if fn.Results == nil {
+		fn.Results = xtypes.EmptyTuple
+	}
+	if recv := sig.Recv(); recv != nil {
+		fn.Params = append(fn.Params, recv.Name())
+	}
+	// InterfaceInputs are indexed by argument position,
+	// receiver included.
+	offset := len(fn.Params)
+	for i := 0; i < sig.Params().Len(); i++ {
+		param := sig.Params().At(i)
+		fn.Params = append(fn.Params, param.Name())
+		if types.IsInterface(param.Type()) {
+			if fn.InterfaceInputs == nil {
+				fn.InterfaceInputs = make(map[int]types.Type)
+			}
+			fn.InterfaceInputs[offset+i] = param.Type()
+		}
+	}
+	conv.instances.funcs[name] = fn
+	conv.instances.queue = append(conv.instances.queue, &Instance{
+		Fn:       fn,
+		Generic:  generic,
+		TypeArgs: targs,
+	})
+	return fn
+}
+
+// funcRef returns function that is referenced by identifier
+// along with its signature.
+// Generic functions are resolved to their instances.
+func (conv *converter) funcRef(id *ast.Ident) (*sexp.Func, *types.Signature) {
+	obj := conv.info.Uses[id].(*types.Func)
+	inst, ok := conv.info.Instances[id]
+	if !ok {
+		return conv.ftab.LookupFunc(obj.Pkg(), obj.Name()), obj.Type().(*types.Signature)
+	}
+	sig := conv.substType(inst.Type).(*types.Signature)
+	targs := make([]types.Type, inst.TypeArgs.Len())
+	for i := range targs {
+		targs[i] = conv.substType(inst.TypeArgs.At(i))
+	}
+	pkgName := conv.pkgFullName(obj.Pkg())
+	fn := conv.instance(
+		symbols.Mangle(pkgName, obj.Name()),
+		symbols.Mangle(pkgName, symbols.InstanceName(obj.Name(), targs)),
+		sig,
+		targs,
+	)
+	return fn, sig
+}
+
+// methodFunc returns function that implements method of named type.
+// Methods of generic type instances are instantiated.
+func (conv *converter) methodFunc(named *types.Named, name string) *sexp.Func {
+	if named.TypeArgs().Len() == 0 {
+		return conv.ftab.LookupMethod(named.Obj(), name)
+	}
+	var method *types.Func
+	for i := 0; i < named.NumMethods(); i++ {
+		if named.Method(i).Name() == name {
+			method = named.Method(i)
+		}
+	}
+	if method == nil {
+		panic(exn.NoImpl("promoted methods of generic types"))
+	}
+	targs := make([]types.Type, named.TypeArgs().Len())
+	for i := range targs {
+		targs[i] = named.TypeArgs().At(i)
+	}
+	obj := named.Obj()
+	pkgName := conv.pkgFullName(obj.Pkg())
+	return conv.instance(
+		symbols.MangleMethod(pkgName, symbols.TypeName(obj), name),
+		symbols.MangleMethod(pkgName, symbols.NamedTypeName(named), name),
+		method.Type().(*types.Signature),
+		targs,
+	)
+}
+
+// instantiateMethods creates generic type instance methods
+// that are referenced by itab.
+func (conv *converter) instantiateMethods(named *types.Named, iface *types.Interface) {
+	if named == nil || named.TypeArgs().Len() == 0 {
+		return
+	}
+	for i := 0; i < iface.NumMethods(); i++ {
+		conv.methodFunc(named, iface.Method(i).Name())
+	}
+}
+
+// instantiatedFunc returns generic function reference
+// without explicit type arguments ("f" for "f[int]").
+// Returns nil if node is not a generic function instantiation.
+func (conv *converter) instantiatedFunc(node ast.Expr) ast.Expr {
+	var x ast.Expr
+	switch node := node.(type) {
+	case *ast.IndexExpr:
+		x = node.X
+	case *ast.IndexListExpr:
+		x = node.X
+	default:
+		return nil
+	}
+	id, ok := unparen(x).(*ast.Ident)
+	if sel, isSel := unparen(x).(*ast.SelectorExpr); isSel {
+		id, ok = sel.Sel, true
+	}
+	if !ok {
+		return nil
+	}
+	if _, ok := conv.info.Uses[id].(*types.Func); !ok {
+		return nil
+	}
+	return x
+}
+
+// substType replaces type parameters of the function
+// instance that is being converted.
+func (conv *converter) substType(typ types.Type) types.Type {
+	if conv.subst == nil {
+		return typ
+	}
+	return conv.subst.typ(typ)
+}
+
+// pkgFullName returns package name that is used for symbol mangling.
+// Imported package paths have "emacs/" prefix.
+func (conv *converter) pkgFullName(p *types.Package) string {
+	if p.Path() == conv.pkg.TypPkg.Path() {
+		return conv.pkg.FullName
+	}
+	return strings.TrimPrefix(p.Path(), "emacs/")
+}
+
+// typeSubst maps type parameters to type arguments.
+// Variables that have type parameters inside their types
+// are replaced by the new variables; the same variable is
+// always replaced by the same object.
+type typeSubst struct {
+	args map[*types.TypeParam]types.Type
+	vars map[*types.Var]*types.Var
+	ctxt *types.Context
+}
+
+func newTypeSubst(ctxt *types.Context, params *types.TypeParamList, args []types.Type) *typeSubst {
+	s := &typeSubst{
+		args: make(map[*types.TypeParam]types.Type, len(args)),
+		vars: make(map[*types.Var]*types.Var),
+		ctxt: ctxt,
+	}
+	for i, arg := range args {
+		s.args[params.At(i)] = arg
+	}
+	return s
+}
+
+// info returns type info where all types and objects are substituted.
+// Selections and instances are not copied; their types
+// must be substituted explicitly.
+func (s *typeSubst) info(info *types.Info) *types.Info {
+	res := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue, len(info.Types)),
+		Defs:       make(map[*ast.Ident]types.Object, len(info.Defs)),
+		Uses:       make(map[*ast.Ident]types.Object, len(info.Uses)),
+		Implicits:  make(map[ast.Node]types.Object, len(info.Implicits)),
+		Selections: info.Selections,
+		Instances:  info.Instances,
+	}
+	for node, tv := range info.Types {
+		tv.Type = s.typ(tv.Type)
+		res.Types[node] = tv
+	}
+	for id, obj := range info.Defs {
+		res.Defs[id] = s.obj(obj)
+	}
+	for id, obj := range info.Uses {
+		res.Uses[id] = s.obj(obj)
+	}
+	for node, obj := range info.Implicits {
+		res.Implicits[node] = s.obj(obj)
+	}
+	return res
+}
+
+func (s *typeSubst) obj(obj types.Object) types.Object {
+	if v, ok := obj.(*types.Var); ok {
+		return s.variable(v)
+	}
+	return obj
+}
+
+func (s *typeSubst) variable(v *types.Var) *types.Var {
+	if v == nil {
+		return nil
+	}
+	if res := s.vars[v]; res != nil {
+		return res
+	}
+	res := v
+	if typ := s.typ(v.Type()); typ != v.Type() {
+		if v.IsField() {
+			res = types.NewField(v.Pos(), v.Pkg(), v.Name(), typ, v.Embedded())
+		} else {
+			res = types.NewVar(v.Pos(), v.Pkg(), v.Name(), typ)
+			res.SetKind(v.Kind())
+		}
+	}
+	s.vars[v] = res
+	return res
+}
+
+func (s *typeSubst) tuple(tuple *types.Tuple) *types.Tuple {
+	if tuple == nil {
+		return nil
+	}
+	vars := make([]*types.Var, tuple.Len())
+	changed := false
+	for i := range vars {
+		vars[i] = s.variable(tuple.At(i))
+		changed = changed || vars[i] != tuple.At(i)
+	}
+	if !changed {
+		return tuple
+	}
+	return types.NewTuple(vars...)
+}
+
+// owns reports whether type parameters are substituted by s.
+func (s *typeSubst) owns(params *types.TypeParamList) bool {
+	if params.Len() == 0 {
+		return false
+	}
+	_, ok := s.args[params.At(0)]
+	return ok
+}
+
+func (s *typeSubst) signature(sig *types.Signature) *types.Signature {
+	ownParams := s.owns(sig.TypeParams()) || s.owns(sig.RecvTypeParams())
+	if !ownParams && (sig.TypeParams().Len() != 0 || sig.RecvTypeParams().Len() != 0) {
+		return sig // Other generic function signature
+	}
+	recv := s.variable(sig.Recv())
+	params, results := s.tuple(sig.Params()), s.tuple(sig.Results())
+	if !ownParams && recv == sig.Recv() && params == sig.Params() && results == sig.Results() {
+		return sig
+	}
+	return types.NewSignatureType(recv, nil, nil, params, results, sig.Variadic())
+}
+
+func (s *typeSubst) typ(typ types.Type) types.Type {
+	switch t := typ.(type) {
+	case *types.TypeParam:
+		if arg, ok := s.args[t]; ok {
+			return arg
+		}
+	case *types.Alias:
+		if res := s.typ(types.Unalias(t)); res != types.Unalias(t) {
+			return res
+		}
+	case *types.Pointer:
+		if elem := s.typ(t.Elem()); elem != t.Elem() {
+			return types.NewPointer(elem)
+		}
+	case *types.Slice:
+		if elem := s.typ(t.Elem()); elem != t.Elem() {
+			return types.NewSlice(elem)
+		}
+	case *types.Array:
+		if elem := s.typ(t.Elem()); elem != t.Elem() {
+			return types.NewArray(elem, t.Len())
+		}
+	case *types.Map:
+		key, elem := s.typ(t.Key()), s.typ(t.Elem())
+		if key != t.Key() || elem != t.Elem() {
+			return types.NewMap(key, elem)
+		}
+	case *types.Chan:
+		if elem := s.typ(t.Elem()); elem != t.Elem() {
+			return types.NewChan(t.Dir(), elem)
+		}
+	case *types.Tuple:
+		return s.tuple(t)
+	case *types.Signature:
+		return s.signature(t)
+	case *types.Struct:
+		return s.structType(t)
+	case *types.Interface:
+		return s.iface(t)
+	case *types.Named:
+		return s.named(t)
+	}
+	return typ
+}
+
+func (s *typeSubst) structType(t *types.Struct) types.Type {
+	fields := make([]*types.Var, t.NumFields())
+	tags := make([]string, t.NumFields())
+	changed := false
+	for i := range fields {
+		fields[i] = s.variable(t.Field(i))
+		tags[i] = t.Tag(i)
+		changed = changed || fields[i] != t.Field(i)
+	}
+	if !changed {
+		return t
+	}
+	return types.NewStruct(fields, tags)
+}
+
+func (s *typeSubst) iface(t *types.Interface) types.Type {
+	methods := make([]*types.Func, t.NumExplicitMethods())
+	changed := false
+	for i := range methods {
+		m := t.ExplicitMethod(i)
+		sig := m.Type().(*types.Signature)
+		methods[i] = m
+		if res := s.signature(sig); res != sig {
+			// Receiver is set by NewInterfaceType.
+			res = types.NewSignatureType(nil, nil, nil, res.Params(), res.Results(), res.Variadic())
+			methods[i] = types.NewFunc(m.Pos(), m.Pkg(), m.Name(), res)
+			changed = true
+		}
+	}
+	embeddeds := make([]types.Type, t.NumEmbeddeds())
+	for i := range embeddeds {
+		embeddeds[i] = s.typ(t.EmbeddedType(i))
+		changed = changed || embeddeds[i] != t.EmbeddedType(i)
+	}
+	if !changed {
+		return t
+	}
+	return types.NewInterfaceType(methods, embeddeds).Complete()
+}
+
+func (s *typeSubst) named(t *types.Named) types.Type {
+	targs := t.TypeArgs()
+	args := make([]types.Type, targs.Len())
+	changed := false
+	for i := range args {
+		args[i] = s.typ(targs.At(i))
+		changed = changed || args[i] != targs.At(i)
+	}
+	if !changed {
+		return t
+	}
+	res, err := types.Instantiate(s.ctxt, t.Origin(), args, false)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
@@ -0,0 +1,97 @@
+package sexpconv
+
+import (
+	"go/token"
+	"go/types"
+	"magic_pkg/emacs/rt"
+	"sexp"
+)
+
+// wrapFunc returns function that truncates integer to
+// the range of specified type; nil if there is no such function.
+//
+// "int" is not wrapped: it is implemented as Elisp integer
+// which is always big enough to satisfy Go spec requirements.
+func wrapFunc(typ *types.Basic) *sexp.Func {
+	switch typ.Kind() {
+	case types.Int8:
+		return rt.FnWrapInt8
+	case types.Int16:
+		return rt.FnWrapInt16
+	case types.Int32:
+		return rt.FnWrapInt32
+	case types.Int64:
+		return rt.FnWrapInt64
+	case types.Uint8:
+		return rt.FnWrapUint8
+	case types.Uint16:
+		return rt.FnWrapUint16
+	case types.Uint32:
+		return rt.FnWrapUint32
+	case types.Uint64, types.Uint, types.Uintptr:
+		return rt.FnWrapUint64
+
+	default:
+		return nil
+	}
+}
+
+// wrapInt truncates integer form to typ range.
+// Forms are returned unchanged unless package
+// requested exact overflow semantics.
+func (conv *converter) wrapInt(form sexp.Form, typ types.Type) sexp.Form {
+	if !conv.pkg.Wraparound {
+		return form
+	}
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		return form
+	}
+	if fn := wrapFunc(basic); fn != nil {
+		return &sexp.TypeCast{Form: sexp.NewCall(fn, form), Typ: typ}
+	}
+	return form
+}
+
+// arith is like arithOp, but it also truncates
+// results of operations that can overflow.
+// Integer division panics if divisor is zero.
+func (conv *converter) arith(op token.Token, typ types.Type, x, y sexp.Form) sexp.Form {
+	form := arithOp(op, x, y)
+	if form == nil {
+		return nil
+	}
+	if (op == token.QUO || op == token.REM) && isInteger(typ) && !isNonZeroInt(y) {
+		fn := rt.FnIntQuo
+		if op == token.REM {
+			fn = rt.FnIntRem
+		}
+		form = &sexp.TypeCast{Form: sexp.NewCall(fn, x, y), Typ: typ}
+	}
+	switch op {
+	case token.ADD, token.SUB, token.MUL, token.SHL:
+		return conv.wrapInt(form, typ)
+	case token.QUO:
+		// Only "MinInt / -1" overflows.
+		if isSigned(typ) {
+			return conv.wrapInt(form, typ)
+		}
+	}
+	return form
+}
+
+// isNonZeroInt reports whether form is non-zero integer constant.
+func isNonZeroInt(form sexp.Form) bool {
+	x, ok := form.(sexp.Int)
+	return ok && x != 0
+}
+
+func isInteger(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsInteger != 0
+}
+
+func isSigned(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&(types.IsInteger|types.IsUnsigned) == types.IsInteger
+}
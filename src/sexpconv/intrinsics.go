@@ -4,6 +4,7 @@ import (
 	"assert"
 	"go/ast"
 	"go/constant"
+	"go/types"
 	"magic_pkg/emacs/lisp"
 	"magic_pkg/emacs/rt"
 	"sexp"
@@ -60,6 +61,10 @@ func (conv *converter) intrinFuncCall(sym string, args []ast.Expr) sexp.Form {
 	case "Intern":
 		return conv.intrinIntern(args[0])
 
+	case "Seq":
+		// Inside "range" statement it is handled specially.
+		return conv.call(rt.FnSeqToSlice, args[0])
+
 	default:
 		fn := lisp.FFI[sym]
 		args := conv.exprList(args)
@@ -67,6 +72,37 @@ func (conv *converter) intrinFuncCall(sym string, args []ast.Expr) sexp.Form {
 	}
 }
 
+// lispSpreadCall converts Lisp function call that
+// passes slice as variadic argument ("lisp.Call(fn, args...)").
+// Slice elements are passed with "apply".
+func (conv *converter) lispSpreadCall(sym string, node *ast.CallExpr) sexp.Form {
+	args := node.Args
+	var fn sexp.Form
+	switch sym {
+	case "Call":
+		if cv := conv.valueOf(args[0]); cv != nil {
+			fn = sexp.Symbol{Val: constant.StringVal(cv)}
+		} else {
+			fn = conv.lispCall(lisp.FnIntern, args[0])
+		}
+		args = args[1:]
+	case "DynCall":
+		fn = conv.Expr(args[0])
+		args = args[1:]
+	default:
+		fn = sexp.Symbol{Val: lisp.FFI[sym].Name}
+	}
+
+	last := len(args) - 1
+	forms := append([]sexp.Form{fn}, conv.exprList(args[:last])...)
+	forms = append(forms, conv.call(rt.FnSliceList, args[last]))
+	call := conv.lispApply(lisp.FnApply, forms)
+	if typ, ok := conv.typeOf(node).(*types.Tuple); ok && typ.Len() == 0 {
+		return call
+	}
+	return &sexp.TypeCast{Form: call, Typ: conv.typeOf(node)}
+}
+
 func (conv *converter) intrinIntern(arg ast.Expr) sexp.Form {
 	if cv := conv.valueOf(arg); cv != nil {
 		s := constant.StringVal(cv)
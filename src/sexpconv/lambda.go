@@ -0,0 +1,344 @@
+package sexpconv
+
+import (
+	"exn"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"magic_pkg/emacs/lisp"
+	"magic_pkg/emacs/rt"
+	"sexp"
+	"strconv"
+	"tu/symbols"
+	"xast"
+	"xtypes"
+)
+
+// lambdaEnv collects functions that are created from function literals.
+type lambdaEnv struct {
+	seq   map[string]int
+	funcs []*sexp.Func
+}
+
+// Lambda names are derived from enclosing function name,
+// so they are stable between different translation units.
+func (env *lambdaEnv) newName(p *xast.Package, outer string) string {
+	prefix := symbols.ManglePriv(p.FullName, "%lambda/"+outer)
+	env.seq[prefix]++
+	return prefix + "/" + strconv.Itoa(env.seq[prefix])
+}
+
+// FuncLit converts function literal into a global function.
+// Captured variables are passed as leading arguments.
+func (conv *converter) FuncLit(node *ast.FuncLit) sexp.Form {
+	sig := conv.typeOf(node).(*types.Signature)
+	captured := capturedVars(conv.info, node)
+
+	fn := &sexp.Func{
+		Name:    conv.lambdas.newName(conv.pkg, conv.funcName),
+		Params:  make([]string, 0, len(captured)+sig.Params().Len()),
+		Results: sig.Results(),
+	}
+	if fn.Results == nil {
+		fn.Results = xtypes.EmptyTuple
+	}
+	capturedForms := make([]sexp.Form, len(captured))
+	for i, v := range captured {
+		fn.Params = append(fn.Params, v.Name())
+		// Boxed variables are passed as is, without unboxing.
+		capturedForms[i] = sexp.Local{Name: v.Name(), Typ: v.Type()}
+	}
+	for i := 0; i < sig.Params().Len(); i++ {
+		fn.Params = append(fn.Params, sig.Params().At(i).Name())
+	}
+	fn.Body = conv.funcBody(sig, fn.Results, node.Body)
+	conv.lambdas.funcs = append(conv.lambdas.funcs, fn)
+
+	return &sexp.Lambda{Fn: fn, Captured: capturedForms, Typ: sig}
+}
+
+// funcValue returns a reference to a top-level function.
+func (conv *converter) funcValue(id *ast.Ident) sexp.Form {
+	if obj := conv.info.Uses[id]; obj.Pkg() == lisp.Package {
+		return sexp.Symbol{Val: lisp.FFI[obj.Name()].Name}
+	}
+	fn, sig := conv.funcRef(id)
+	return &sexp.Lambda{Fn: fn, Typ: sig}
+}
+
+// methodValue converts "x.method" that is not called immediately
+// into a closure that has receiver bound.
+// Receiver is evaluated (and copied) when method value is created.
+func (conv *converter) methodValue(node *ast.SelectorExpr, sel *types.Selection) sexp.Form {
+	if xtypes.AsNamedType(sel.Recv()) == lisp.TypObject {
+		panic(exn.Conv(conv.fileSet, "can't take lisp.Object method value", node))
+	}
+	sig := conv.typeOf(node).(*types.Signature)
+	recv, named := conv.methodRecv(conv.recvExpr(node, sel), conv.typeOf(node.X), sel)
+	if iface, ok := named.Underlying().(*types.Interface); ok {
+		return &sexp.TypeCast{
+			Form: conv.call(
+				rt.FnIfaceMethodValue,
+				recv,
+				sexp.Int(xtypes.LookupIfaceMethod(node.Sel.Name, iface)),
+			),
+			Typ: sig,
+		}
+	}
+
+	recv = conv.copyValue(recv, nil)
+	return &sexp.Lambda{
+		Fn:       conv.methodFunc(named, node.Sel.Name),
+		Captured: []sexp.Form{recv},
+		Typ:      sig,
+	}
+}
+
+// methodExpr converts "T.method" into a reference to the
+// method function; receiver becomes its first parameter.
+func (conv *converter) methodExpr(node *ast.SelectorExpr, sel *types.Selection) sexp.Form {
+	named := xtypes.AsNamedType(conv.substType(sel.Recv()))
+	if types.IsInterface(named) {
+		panic(exn.NoImpl("interface method expressions"))
+	}
+	// Promoted methods are resolved to their wrappers.
+	fn := conv.methodFunc(named, node.Sel.Name)
+	if fn == nil {
+		panic(exn.NoImpl("method expression for %s.%s", named.Obj().Name(), node.Sel.Name))
+	}
+	return &sexp.Lambda{Fn: fn, Typ: conv.typeOf(node).(*types.Signature)}
+}
+
+// dynCall invokes function value.
+func (conv *converter) dynCall(node *ast.CallExpr) sexp.Form {
+	sig := conv.typeOf(node.Fun).Underlying().(*types.Signature)
+	params := sig.Params()
+	forms := conv.argList(sig, node)
+	for i, form := range forms {
+		forms[i] = conv.copyValue(form, params.At(i).Type())
+	}
+
+	var typ types.Type = xtypes.EmptyTuple
+	if results := sig.Results(); results.Len() == 1 {
+		typ = results.At(0).Type()
+	} else if results != nil {
+		typ = results
+	}
+	return &sexp.DynCall{Callable: conv.Expr(node.Fun), Args: forms, Typ: typ}
+}
+
+// Thunks are functions without parameters.
+var thunkSig = types.NewSignature(nil, nil, nil, false)
+
+// thunk converts call expression into a function value
+// without parameters ("defer" and "go" statements).
+// Arguments are evaluated immediately.
+func (conv *converter) thunk(node *ast.CallExpr) sexp.Form {
+	form := conv.Expr(node)
+	if cast, ok := form.(*sexp.TypeCast); ok {
+		form = cast.Form
+	}
+
+	switch form := form.(type) {
+	case *sexp.Call:
+		return &sexp.Lambda{Fn: form.Fn, Captured: form.Args, Typ: thunkSig}
+	case *sexp.LispCall:
+		return partialCall(sexp.Symbol{Val: form.Fn.Name}, form.Args)
+	case *sexp.DynCall:
+		return partialCall(form.Callable, form.Args)
+
+	default:
+		panic(exn.Conv(conv.fileSet, "can't defer or spawn", node))
+	}
+}
+
+func partialCall(fn sexp.Form, args []sexp.Form) sexp.Form {
+	if len(args) == 0 {
+		return fn
+	}
+	return sexp.NewLispCall(lisp.FnApplyPartially, append([]sexp.Form{fn}, args...)...)
+}
+
+func (conv *converter) funcBody(sig *types.Signature, results *types.Tuple, node *ast.BlockStmt) sexp.Block {
+	prevRetType, prevCtxType := conv.retType, conv.ctxType
+	prevDeferring := conv.deferring
+	conv.retType = results
+	conv.deferring = hasDefer(node)
+
+	forms := conv.boxParams(sig)
+	forms = append(forms, conv.bindResults(results)...)
+	if conv.deferring {
+		forms = append(forms, conv.deferBody(results, node)...)
+	} else {
+		forms = append(forms, conv.stmtList(node.List)...)
+		// Adding return statement.
+		// It is needed in void functions without explicit "return".
+		if results.Len() == 0 {
+			forms = append(forms, &sexp.Return{})
+		}
+	}
+
+	conv.retType, conv.ctxType = prevRetType, prevCtxType
+	conv.deferring = prevDeferring
+	return sexp.Block(forms)
+}
+
+// boxParams generates code that replaces boxed
+// parameters values with their boxes.
+func (conv *converter) boxParams(sig *types.Signature) []sexp.Form {
+	var forms []sexp.Form
+	params := make([]*types.Var, 0, sig.Params().Len()+1)
+	if recv := sig.Recv(); recv != nil {
+		params = append(params, recv)
+	}
+	for i := 0; i < sig.Params().Len(); i++ {
+		params = append(params, sig.Params().At(i))
+	}
+	for _, v := range params {
+		if conv.boxed[v] {
+			forms = append(forms, &sexp.Rebind{
+				Name: v.Name(),
+				Expr: box(sexp.Local{Name: v.Name(), Typ: v.Type()}),
+			})
+		}
+	}
+	return forms
+}
+
+// Variables that are both captured by closure and assigned
+// are stored inside a box (single element list).
+// This way all closures and enclosing function share the same value.
+
+func box(form sexp.Form) sexp.Form {
+	return sexp.NewLispCall(lisp.FnList, form)
+}
+
+func unbox(name string, typ types.Type) sexp.Form {
+	return &sexp.TypeCast{
+		Form: sexp.NewLispCall(lisp.FnCar, sexp.Local{Name: name, Typ: typ}),
+		Typ:  typ,
+	}
+}
+
+func setBoxed(name string, typ types.Type, expr sexp.Form) sexp.Form {
+	return &sexp.ExprStmt{
+		Expr: sexp.NewLispCall(lisp.FnSetcar, sexp.Local{Name: name, Typ: typ}, expr),
+	}
+}
+
+func (conv *converter) isBoxed(obj types.Object) bool {
+	v, ok := obj.(*types.Var)
+	return ok && conv.boxed[v]
+}
+
+// capturedVars returns local variables that are referenced
+// inside function literal, but declared outside of it.
+func capturedVars(info *types.Info, lit *ast.FuncLit) []*types.Var {
+	var vars []*types.Var
+	seen := make(map[*types.Var]bool)
+	ast.Inspect(lit.Body, func(node ast.Node) bool {
+		ident, ok := node.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		v, ok := info.Uses[ident].(*types.Var)
+		if !ok || seen[v] || v.IsField() || xtypes.IsGlobal(v) {
+			return true
+		}
+		if v.Pos() < lit.Pos() || v.Pos() >= lit.End() {
+			seen[v] = true
+			vars = append(vars, v)
+		}
+		return true
+	})
+	return vars
+}
+
+// boxedVars returns variables that should be boxed inside given node.
+// Results are named results of the function that owns root (can be nil).
+func boxedVars(info *types.Info, root ast.Node, results *types.Tuple) map[*types.Var]bool {
+	captured := make(map[*types.Var]bool)
+	assigned := make(map[*types.Var]bool)
+	boxed := make(map[*types.Var]bool)
+
+	markAssigned := func(node ast.Expr) {
+		if ident, ok := unparen(node).(*ast.Ident); ok {
+			if v, ok := info.Uses[ident].(*types.Var); ok {
+				assigned[v] = true
+			}
+		}
+	}
+	// Variables that have their address taken are always boxed.
+	// Pointers to structs and arrays refer to the object itself.
+	markAddressed := func(node ast.Expr) {
+		if ident, ok := unparen(node).(*ast.Ident); ok {
+			v, ok := info.Uses[ident].(*types.Var)
+			if ok && !xtypes.IsGlobal(v) && !isRefPointee(v.Type()) {
+				boxed[v] = true
+			}
+		}
+	}
+
+	// "return x" assigns every named result.
+	markReturns := func(body ast.Node, results *types.Tuple) {
+		if results == nil || results.Len() == 0 || results.At(0).Name() == "" {
+			return
+		}
+		ast.Inspect(body, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				if len(node.Results) != 0 {
+					for i := 0; i < results.Len(); i++ {
+						assigned[results.At(i)] = true
+					}
+				}
+			}
+			return true
+		})
+	}
+	markReturns(root, results)
+
+	ast.Inspect(root, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			for _, v := range capturedVars(info, node) {
+				captured[v] = true
+			}
+			if sig, ok := info.TypeOf(node).(*types.Signature); ok {
+				markReturns(node.Body, sig.Results())
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				markAssigned(lhs)
+			}
+		case *ast.IncDecStmt:
+			markAssigned(node.X)
+		case *ast.UnaryExpr:
+			if node.Op == token.AND {
+				markAddressed(node.X)
+			}
+		case *ast.SelectorExpr:
+			// Pointer receiver method call takes address implicitly.
+			if sel := info.Selections[node]; sel != nil && needsRecvAddr(info, node, sel) {
+				markAddressed(node.X)
+			}
+		case *ast.RangeStmt:
+			if node.Tok == token.ASSIGN {
+				markAssigned(node.Key)
+				if node.Value != nil {
+					markAssigned(node.Value)
+				}
+			}
+		}
+		return true
+	})
+
+	for v := range captured {
+		if assigned[v] {
+			boxed[v] = true
+		}
+	}
+	return boxed
+}
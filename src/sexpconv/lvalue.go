@@ -0,0 +1,202 @@
+package sexpconv
+
+import (
+	"go/ast"
+	"go/types"
+	"magic_pkg/emacs/lisp"
+	"magic_pkg/emacs/rt"
+	"sexp"
+	"strconv"
+	"xtypes"
+)
+
+// lvalue is an assignment target which operands are
+// evaluated only once (they are bound to temporaries).
+type lvalue struct {
+	load  func() sexp.Form
+	store func(expr sexp.Form) sexp.Form
+}
+
+// tmpEnv collects temporaries bindings.
+type tmpEnv struct {
+	forms []sexp.Form
+	// If true, locals are also bound to temporaries.
+	// Needed when target operands may be changed by other
+	// assignments of the same statement ("i, a[i] = 1, 2").
+	strict bool
+}
+
+func (env *tmpEnv) bind(form sexp.Form) sexp.Form {
+	switch form.(type) {
+	case sexp.Bool, sexp.Int, sexp.Float, sexp.Str, sexp.Symbol:
+		return form
+	case sexp.Local, sexp.Var:
+		if !env.strict {
+			return form
+		}
+	}
+	name := "%tmp" + strconv.Itoa(len(env.forms))
+	env.forms = append(env.forms, &sexp.Bind{Name: name, Init: form})
+	return sexp.Local{Name: name, Typ: form.Type()}
+}
+
+// lvalue converts addressable expression (or map index expression)
+// into assignment target.
+// Addressable struct and array targets are updated in place.
+func (conv *converter) lvalue(env *tmpEnv, node ast.Expr) lvalue {
+	lv := conv.lvalueOf(env, node)
+	if typ := conv.typeOf(node); isRefPointee(typ) && conv.info.Types[node].Addressable() {
+		lv.store = func(expr sexp.Form) sexp.Form {
+			return conv.copyInto(lv.load(), expr, typ, 0)
+		}
+	}
+	return lv
+}
+
+func (conv *converter) lvalueOf(env *tmpEnv, node ast.Expr) lvalue {
+	node = unparen(node)
+	typ := conv.typeOf(node)
+	load := func(form sexp.Form) func() sexp.Form {
+		return func() sexp.Form { return form }
+	}
+
+	switch node := node.(type) {
+	case *ast.SelectorExpr:
+		sel := conv.info.Selections[node]
+		if sel == nil {
+			break // Qualified global variable
+		}
+		path := sel.Index()
+		x, xTyp := selectPath(conv.Expr(node.X), conv.typeOf(node.X), path[:len(path)-1])
+		x = env.bind(x)
+		structTyp := xtypes.MaybeDeref(xTyp).Underlying().(*types.Struct)
+		index := path[len(path)-1]
+		return lvalue{
+			load: load(&sexp.StructIndex{Struct: x, Index: index, Typ: structTyp}),
+			store: func(expr sexp.Form) sexp.Form {
+				return &sexp.StructUpdate{
+					Struct: x,
+					Index:  index,
+					Expr:   conv.copyValue(expr, typ),
+					Typ:    structTyp,
+				}
+			},
+		}
+
+	case *ast.IndexExpr:
+		x := env.bind(conv.Expr(node.X))
+		var key sexp.Form
+		if typ, ok := conv.typeOf(node.X).Underlying().(*types.Map); ok {
+			key = env.bind(conv.mapKey(node.Index, typ))
+		} else {
+			key = env.bind(conv.Expr(node.Index))
+		}
+		switch conv.typeOf(node.X).Underlying().(type) {
+		case *types.Map:
+			get := sexp.NewLispCall(lisp.FnGethash, key, x, ZeroValue(typ))
+			return lvalue{
+				load: load(&sexp.TypeCast{Form: get, Typ: typ}),
+				store: func(expr sexp.Form) sexp.Form {
+					return &sexp.ExprStmt{Expr: conv.call(rt.FnMapInsert, key, conv.copyValue(expr, typ), x)}
+				},
+			}
+
+		case *types.Slice:
+			get := sexp.NewCall(rt.FnSliceGet, x, key)
+			return lvalue{
+				load: load(&sexp.TypeCast{Form: get, Typ: typ}),
+				store: func(expr sexp.Form) sexp.Form {
+					return &sexp.ExprStmt{Expr: conv.call(rt.FnSliceSet, x, key, conv.copyValue(expr, typ))}
+				},
+			}
+
+		case *types.Array, *types.Pointer:
+			// Pointer to array is the array itself.
+			return lvalue{
+				load: load(&sexp.ArrayIndex{Array: x, Index: key}),
+				store: func(expr sexp.Form) sexp.Form {
+					return &sexp.ArrayUpdate{
+						Array: x,
+						Index: key,
+						Expr:  conv.uintElem(conv.copyValue(expr, typ), typ),
+					}
+				},
+			}
+
+		default:
+			panic(errUnexpectedExpr(conv, node))
+		}
+
+	case *ast.StarExpr:
+		ptr := env.bind(conv.Expr(node.X))
+		var get sexp.Form = ptr
+		if !isRefPointee(typ) {
+			get = sexp.NewCall(rt.FnPtrLoad, ptr)
+		}
+		return lvalue{
+			load: load(&sexp.TypeCast{Form: get, Typ: typ}),
+			store: func(expr sexp.Form) sexp.Form {
+				return conv.derefStore(ptr, typ, conv.copyValue(expr, typ))
+			},
+		}
+	}
+
+	// Variables are assigned directly.
+	return lvalue{
+		load: func() sexp.Form { return conv.Expr(node) },
+		store: func(expr sexp.Form) sexp.Form {
+			return conv.assign(node, expr)
+		},
+	}
+}
+
+// update = "lhs = fn(lhs)".
+// Used for compound assignments and "++"/"--" statements.
+func (conv *converter) update(lhs ast.Expr, fn func(x sexp.Form) sexp.Form) sexp.Form {
+	env := &tmpEnv{}
+	lv := conv.lvalue(env, lhs)
+	form := lv.store(fn(lv.load()))
+	if len(env.forms) == 0 {
+		return form
+	}
+	return sexp.Block(append(env.forms, form))
+}
+
+// parallelAssign = "x1, x2 = y1, y2".
+// Target operands and all values are evaluated
+// before any assignment is performed.
+func (conv *converter) parallelAssign(lhs, rhs []ast.Expr) sexp.FormList {
+	env := &tmpEnv{strict: true}
+	targets := make([]lvalue, len(lhs))
+	for i, lhs := range lhs {
+		if isBlankIdent(lhs) {
+			// Value is still evaluated in order, but not stored.
+			targets[i] = lvalue{store: conv.ignoredExpr}
+			continue
+		}
+		targets[i] = conv.lvalue(env, lhs)
+	}
+	vals := make([]sexp.Form, len(rhs))
+	for i, rhs := range rhs {
+		if isBlankIdent(lhs[i]) {
+			conv.ctxType = nil
+			vals[i] = env.bind(conv.Expr(rhs))
+			continue
+		}
+		typ := conv.typeOf(lhs[i])
+		conv.ctxType = typ
+		if isRefPointee(typ) {
+			// Targets are updated in place, so values that
+			// refer to them are copied before any store.
+			vals[i] = env.bind(conv.copyValue(conv.Expr(rhs), typ))
+			continue
+		}
+		vals[i] = env.bind(conv.Expr(rhs))
+	}
+
+	forms := env.forms
+	for i, target := range targets {
+		forms = append(forms, target.store(vals[i]))
+	}
+	return sexp.FormList(forms)
+}
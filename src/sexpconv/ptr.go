@@ -0,0 +1,205 @@
+package sexpconv
+
+import (
+	"exn"
+	"go/ast"
+	"go/types"
+	"magic_pkg/emacs/lisp"
+	"magic_pkg/emacs/rt"
+	"sexp"
+	"strconv"
+	"vmm"
+	"xtypes"
+)
+
+// Locals that hold "*p = x" operands for struct and array pointers.
+const (
+	ptrDst   = "%ptr"
+	ptrSrc   = "%val"
+	ptrIndex = "%idx" // Array element index
+)
+
+// isRefPointee reports whether pointers to the given type are
+// represented by pointed objects themselves (structs and arrays).
+// Other pointers are "rt.Ptr" objects.
+func isRefPointee(typ types.Type) bool {
+	return xtypes.IsStruct(typ) || xtypes.IsArray(typ)
+}
+
+func isPointer(typ types.Type) bool {
+	_, ok := typ.Underlying().(*types.Pointer)
+	return ok
+}
+
+// addrOf = "&x".
+func (conv *converter) addrOf(node ast.Expr) sexp.Form {
+	node = unparen(node)
+	typ := conv.typeOf(node)
+	ptrTyp := types.NewPointer(typ)
+	if isRefPointee(typ) {
+		return conv.takeAddr(conv.Expr(node))
+	}
+
+	var ptr sexp.Form
+	switch node := node.(type) {
+	case *ast.Ident:
+		ptr = conv.varAddr(nil, conv.info.Uses[node].(*types.Var))
+
+	case *ast.SelectorExpr:
+		sel := conv.info.Selections[node]
+		if sel == nil {
+			// Qualified global variable.
+			v := conv.info.Uses[node.Sel].(*types.Var)
+			ptr = conv.varAddr(v.Pkg(), v)
+			break
+		}
+		path := sel.Index()
+		x, typ := selectPath(conv.Expr(node.X), conv.typeOf(node.X), path[:len(path)-1])
+		structTyp := xtypes.MaybeDeref(typ).Underlying().(*types.Struct)
+		ptr = fieldAddr(x, structTyp, path[len(path)-1])
+
+	case *ast.IndexExpr:
+		switch typ := conv.typeOf(node.X).Underlying().(type) {
+		case *types.Slice:
+			ptr = sexp.NewCall(rt.FnSlicePtr, conv.Expr(node.X), conv.Expr(node.Index))
+		case *types.Array, *types.Pointer:
+			ptr = sexp.NewCall(rt.FnVectorPtr, conv.Expr(node.X), conv.Expr(node.Index))
+		default:
+			panic(exn.Conv(conv.fileSet, "can't take address of "+typ.String()+" element", node))
+		}
+
+	case *ast.StarExpr:
+		return conv.Expr(node.X) // "&*p" is "p"
+
+	case *ast.CompositeLit:
+		ptr = sexp.NewCall(rt.FnCarPtr, box(conv.Expr(node)))
+
+	default:
+		panic(exn.Conv(conv.fileSet, "can't take address of", node))
+	}
+	return &sexp.TypeCast{Form: ptr, Typ: ptrTyp}
+}
+
+// varAddr returns pointer to the variable.
+// Package is nil for unqualified global variables.
+// Local variables that have their address taken are boxed.
+func (conv *converter) varAddr(pkg *types.Package, v *types.Var) sexp.Form {
+	if xtypes.IsGlobal(v) {
+		sym := sexp.Symbol{Val: conv.env.InternVar(pkg, v.Name())}
+		return sexp.NewCall(rt.FnSymbolPtr, sym)
+	}
+	if !conv.isBoxed(v) {
+		panic(exn.Logic("taking address of unboxed `%s' variable", v.Name()))
+	}
+	return sexp.NewCall(rt.FnCarPtr, sexp.Local{Name: v.Name(), Typ: v.Type()})
+}
+
+// fieldAddr returns pointer to the struct field.
+// Pointer refers to the struct object slot,
+// so it depends on the struct representation.
+func fieldAddr(x sexp.Form, typ *types.Struct, index int) sexp.Form {
+	switch vmm.StructReprOf(typ) {
+	case vmm.StructUnit:
+		return sexp.NewCall(rt.FnCarPtr, x)
+	case vmm.StructCons:
+		if index == typ.NumFields()-1 {
+			// Last field is stored inside cdr.
+			return sexp.NewCall(rt.FnCdrPtr, nthcdr(index-1, x))
+		}
+		return sexp.NewCall(rt.FnCarPtr, nthcdr(index, x))
+	default:
+		return sexp.NewCall(rt.FnVectorPtr, x, sexp.Int(index))
+	}
+}
+
+func nthcdr(n int, x sexp.Form) sexp.Form {
+	if n == 0 {
+		return x
+	}
+	return sexp.NewLispCall(lisp.FnNthcdr, sexp.Int(n), x)
+}
+
+// deref = "*p".
+// Struct and array values are not copied here; just like
+// variables, they are copied when assigned or passed.
+func (conv *converter) deref(node *ast.StarExpr) sexp.Form {
+	elem := conv.typeOf(node)
+	x := conv.Expr(node.X)
+	if isRefPointee(elem) {
+		return &sexp.TypeCast{Form: x, Typ: elem}
+	}
+	return &sexp.TypeCast{Form: sexp.NewCall(rt.FnPtrLoad, x), Typ: elem}
+}
+
+// derefAssign = "*p = expr".
+func (conv *converter) derefAssign(node *ast.StarExpr, expr sexp.Form) sexp.Form {
+	return conv.derefStore(conv.Expr(node.X), conv.typeOf(node), expr)
+}
+
+// derefStore stores expr into the object that ptr points to.
+// Struct and array values are copied into pointed object.
+func (conv *converter) derefStore(ptr sexp.Form, elem types.Type, expr sexp.Form) sexp.Form {
+	if isRefPointee(elem) {
+		return conv.copyInto(ptr, expr, elem, 0)
+	}
+	return &sexp.ExprStmt{Expr: sexp.NewCall(rt.FnPtrStore, ptr, expr)}
+}
+
+// copyInto copies struct or array value into dst object.
+// Pointers to dst (or to its struct and array parts)
+// remain valid and observe the new value.
+// Depth makes temporaries of nested copies distinct.
+func (conv *converter) copyInto(dst, src sexp.Form, typ types.Type, depth int) sexp.Form {
+	suffix := ""
+	if depth != 0 {
+		suffix = strconv.Itoa(depth)
+	}
+	dstLocal := sexp.Local{Name: ptrDst + suffix, Typ: dst.Type()}
+	srcLocal := sexp.Local{Name: ptrSrc + suffix, Typ: typ}
+	forms := sexp.Block{
+		&sexp.Bind{Name: dstLocal.Name, Init: dst},
+		&sexp.Bind{Name: srcLocal.Name, Init: src},
+	}
+
+	if typ, ok := typ.Underlying().(*types.Array); ok {
+		if !isRefPointee(typ.Elem()) {
+			return append(forms, &sexp.ExprStmt{
+				Expr: sexp.NewCall(rt.FnArrayAssign, dstLocal, srcLocal),
+			})
+		}
+		index := sexp.Local{Name: ptrIndex + suffix, Typ: types.Typ[types.Int]}
+		return append(forms, &sexp.While{
+			Init: &sexp.Bind{Name: index.Name, Init: sexp.Int(0)},
+			Cond: sexp.NewNumLt(index, sexp.Int(typ.Len())),
+			Post: &sexp.Rebind{Name: index.Name, Expr: sexp.NewAdd1(index)},
+			Body: sexp.Block{conv.copyInto(
+				&sexp.ArrayIndex{Array: dstLocal, Index: index},
+				&sexp.ArrayIndex{Array: srcLocal, Index: index},
+				typ.Elem(),
+				depth+1,
+			)},
+		})
+	}
+
+	structTyp := typ.Underlying().(*types.Struct)
+	for i := 0; i < structTyp.NumFields(); i++ {
+		field := structTyp.Field(i).Type()
+		val := &sexp.StructIndex{Struct: srcLocal, Index: i, Typ: structTyp}
+		if isRefPointee(field) {
+			forms = append(forms, conv.copyInto(
+				&sexp.StructIndex{Struct: dstLocal, Index: i, Typ: structTyp},
+				val,
+				field,
+				depth+1,
+			))
+			continue
+		}
+		forms = append(forms, &sexp.StructUpdate{
+			Struct: dstLocal,
+			Index:  i,
+			Expr:   conv.copyValue(val, field),
+			Typ:    structTyp,
+		})
+	}
+	return forms
+}
@@ -1,7 +1,6 @@
 package sexpconv
 
 import (
-	"assert"
 	"go/ast"
 	"go/constant"
 	"magic_pkg/emacs/lisp"
@@ -17,10 +16,23 @@ func sexpConst(conv *converter, expr ast.Expr) sexp.Form {
 		return sexp.Symbol{Val: constant.StringVal(cv)}
 	}
 
+	// Integral float constants are represented as Int values.
+	if isFloat(conv.typeOf(expr)) {
+		val, _ := constant.Float64Val(cv)
+		return sexp.Float(val)
+	}
+
 	switch cv.Kind() {
 	case constant.Int:
 		val, exact := constant.Int64Val(cv)
-		assert.True(exact)
+		if !exact {
+			// Only uint64 constants can be out of int64 range.
+			// They are represented by bignums.
+			return &sexp.TypeCast{
+				Form: sexp.NewLispCall(lisp.FnStringToNumber, sexp.Str(cv.ExactString())),
+				Typ:  conv.typeOf(expr),
+			}
+		}
 		return sexp.Int(val)
 
 	case constant.Float:
@@ -12,9 +12,13 @@ import (
 )
 
 type Converter struct {
-	env     *symbols.Env
-	ftab    *symbols.FuncTable
-	itabEnv *symbols.ItabEnv
+	env       *symbols.Env
+	ftab      *symbols.FuncTable
+	itabEnv   *symbols.ItabEnv
+	lambdas   lambdaEnv
+	instances instanceEnv
+	// Generated map key test functions; see keyTest.
+	keyTests map[string][2]*sexp.Lambda
 }
 
 func (conv *Converter) FuncTable() *symbols.FuncTable {
@@ -25,51 +29,97 @@ func (conv *Converter) Env() *symbols.Env {
 	return conv.env
 }
 
+// TakeLambdas returns functions that were created from
+// function literals since the last TakeLambdas call.
+func (conv *Converter) TakeLambdas() []*sexp.Func {
+	funcs := conv.lambdas.funcs
+	conv.lambdas.funcs = nil
+	return funcs
+}
+
 type converter struct {
 	info    *types.Info
 	fileSet *token.FileSet
 
-	env     *symbols.Env
-	ftab    *symbols.FuncTable
-	itabEnv *symbols.ItabEnv
+	env       *symbols.Env
+	ftab      *symbols.FuncTable
+	itabEnv   *symbols.ItabEnv
+	lambdas   *lambdaEnv
+	instances *instanceEnv
+	keyTests  map[string][2]*sexp.Lambda
+
+	pkg *xast.Package
+	// Name of the function that is being converted.
+	funcName string
+	// Variables that are captured by closures and should be boxed.
+	boxed map[*types.Var]bool
+	// Type parameters substitution; nil unless
+	// generic function instance is being converted.
+	subst *typeSubst
 
 	// Context type is used to resolve "untyped" constants.
 	ctxType types.Type
 	// Type that should be used for ctxType inside "return" statements.
 	retType *types.Tuple
+	// True if function that is being converted uses "defer".
+	deferring bool
+
+	// Enclosing loop, switch and select statements.
+	targets   []*branchTarget
+	targetSeq int
+	// Label of the statement that is about to be converted.
+	stmtLabel string
 }
 
 func NewConverter(ftab *symbols.FuncTable, env *symbols.Env, itabEnv *symbols.ItabEnv) *Converter {
-	return &Converter{env: env, ftab: ftab, itabEnv: itabEnv}
+	return &Converter{
+		env:     env,
+		ftab:    ftab,
+		itabEnv: itabEnv,
+		lambdas: lambdaEnv{seq: make(map[string]int)},
+		instances: instanceEnv{
+			funcs: make(map[string]*sexp.Func),
+			ctxt:  types.NewContext(),
+		},
+		keyTests: make(map[string][2]*sexp.Lambda),
+	}
 }
 
 func (conv *Converter) newConverter(p *xast.Package) converter {
 	return converter{
-		info:    p.Info,
-		fileSet: p.FileSet,
-		env:     conv.env,
-		ftab:    conv.ftab,
-		itabEnv: conv.itabEnv,
+		info:      p.Info,
+		fileSet:   p.FileSet,
+		env:       conv.env,
+		ftab:      conv.ftab,
+		itabEnv:   conv.itabEnv,
+		lambdas:   &conv.lambdas,
+		instances: &conv.instances,
+		keyTests:  conv.keyTests,
+		pkg:       p,
 	}
 }
 
 func (conv *Converter) VarInit(assign *xast.Assign) sexp.Form {
 	c := conv.newConverter(assign.Pkg)
+	c.funcName = "init"
+	c.boxed = boxedVars(c.info, assign.Rhs, nil)
 	return c.VarInit(assign.Lhs, assign.Rhs)
 }
 
 func (conv *Converter) FuncBody(fn *xast.Func) sexp.Block {
 	c := conv.newConverter(fn.Pkg)
-	c.retType = fn.Ret
-	body := c.BlockStmt(fn.Body)
-
-	// Adding return statement.
-	// It is needed in void functions without explicit "return".
-	if fn.Ret == xtypes.EmptyTuple {
-		body = append(body, &sexp.Return{})
+	c.funcName = fn.Name
+	sig, ret := fn.Sig, fn.Ret
+	if len(fn.TypeArgs) != 0 {
+		c.subst = newTypeSubst(conv.instances.ctxt, fn.TypeParams, fn.TypeArgs)
+		c.info = c.subst.info(c.info)
+		sig = c.subst.signature(sig)
+		if ret = sig.Results(); ret == nil {
+			ret = xtypes.EmptyTuple
+		}
 	}
-
-	return body
+	c.boxed = boxedVars(c.info, fn.Body, ret)
+	return c.funcBody(sig, ret, fn.Body)
 }
 
 func (conv *Converter) VarZeroInit(sym string, typ types.Type) sexp.Form {
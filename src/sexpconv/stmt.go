@@ -3,6 +3,7 @@ package sexpconv
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
 	"sexp"
 	"xast"
 )
@@ -29,10 +30,20 @@ func (conv *converter) Stmt(node ast.Stmt) sexp.Form {
 		return conv.RangeStmt(node)
 	case *ast.SwitchStmt:
 		return conv.SwitchStmt(node)
+	case *ast.TypeSwitchStmt:
+		return conv.TypeSwitchStmt(node)
 	case *ast.BranchStmt:
 		return conv.BranchStmt(node)
 	case *ast.LabeledStmt:
 		return conv.LabeledStmt(node)
+	case *ast.DeferStmt:
+		return conv.DeferStmt(node)
+	case *ast.GoStmt:
+		return conv.GoStmt(node)
+	case *ast.SendStmt:
+		return conv.SendStmt(node)
+	case *ast.SelectStmt:
+		return conv.SelectStmt(node)
 	case *ast.EmptyStmt:
 		return sexp.EmptyForm
 
@@ -69,8 +80,21 @@ func (conv *converter) withInitStmt(init ast.Stmt, form sexp.Form) sexp.Form {
 	}
 }
 
-func (conv *converter) ReturnStmt(node *ast.ReturnStmt) *sexp.Return {
-	// #FIXME: will not work for "naked" returns.
+func (conv *converter) ReturnStmt(node *ast.ReturnStmt) sexp.Form {
+	if conv.deferring {
+		return conv.deferReturn(node)
+	}
+	if len(node.Results) == 0 && conv.retType.Len() != 0 {
+		// Naked return.
+		results := make([]sexp.Form, conv.retType.Len())
+		for i := range results {
+			results[i] = conv.resultValue(i)
+		}
+		return &sexp.Return{Results: results}
+	}
+	if len(node.Results) == 1 && conv.retType.Len() > 1 {
+		return conv.forwardResults(node.Results[0])
+	}
 	results := make([]sexp.Form, len(node.Results))
 	for i, node := range node.Results {
 		typ := conv.retType.At(i).Type()
@@ -90,11 +114,29 @@ func (conv *converter) DeclStmt(node *ast.DeclStmt) sexp.Form {
 	switch decl.Tok {
 	case token.VAR:
 		return conv.varDecl(decl)
+	case token.CONST, token.TYPE:
+		// Constants are folded and types are
+		// resolved by their uses.
+		return sexp.EmptyForm
 	}
 
 	panic(errUnexpectedStmt(conv, node))
 }
 
+// forwardResults converts "return f()" where f is multi-valued.
+// Packed results are returned as is, unless they
+// need to be converted to the result types.
+func (conv *converter) forwardResults(node ast.Expr) sexp.Form {
+	if types.Identical(conv.typeOf(node), conv.retType) {
+		return &sexp.Return{Results: []sexp.Form{conv.Expr(node)}}
+	}
+	bind, results := conv.rhsMultiValues(node)
+	for i := range results {
+		results[i] = conv.copyValue(results[i], conv.retType.At(i).Type())
+	}
+	return sexp.Block{bind, &sexp.Return{Results: results}}
+}
+
 func (conv *converter) varDecl(node *ast.GenDecl) sexp.FormList {
 	forms := make([]sexp.Form, 0, 1)
 
@@ -109,7 +151,12 @@ func (conv *converter) valueSpec(forms []sexp.Form, spec *ast.ValueSpec) []sexp.
 	if len(spec.Values) == 0 {
 		zv := ZeroValue(conv.typeOf(spec.Type))
 		for _, ident := range spec.Names {
-			if ident.Name != "_" {
+			if ident.Name == "_" {
+				continue
+			}
+			if conv.isBoxed(conv.info.Defs[ident]) {
+				forms = append(forms, &sexp.Bind{Name: ident.Name, Init: box(zv)})
+			} else {
 				forms = append(forms, &sexp.Bind{Name: ident.Name, Init: zv})
 			}
 		}
@@ -121,17 +168,13 @@ func (conv *converter) valueSpec(forms []sexp.Form, spec *ast.ValueSpec) []sexp.
 }
 
 func (conv *converter) IncDecStmt(node *ast.IncDecStmt) sexp.Form {
-	target := node.X.(*ast.Ident) // #FIXME: should be any "addressable".
-	if node.Tok == token.INC {
-		return &sexp.Rebind{
-			Name: target.Name,
-			Expr: sexp.NewAdd1(conv.Expr(target)),
+	typ := conv.typeOf(node.X)
+	return conv.update(node.X, func(x sexp.Form) sexp.Form {
+		if node.Tok == token.INC {
+			return conv.wrapInt(sexp.NewAdd1(x), typ)
 		}
-	}
-	return &sexp.Rebind{
-		Name: target.Name,
-		Expr: sexp.NewSub1(conv.Expr(target)),
-	}
+		return conv.wrapInt(sexp.NewSub1(x), typ)
+	})
 }
 
 func (conv *converter) ExprStmt(node *ast.ExprStmt) sexp.Form {
@@ -1,12 +1,24 @@
 package sexpconv
 
 import (
+	"exn"
 	"go/ast"
+	"go/token"
+	"go/types"
+	"magic_pkg/emacs/lisp"
+	"magic_pkg/emacs/rt"
 	"sexp"
 )
 
 func (conv *converter) SwitchStmt(node *ast.SwitchStmt) sexp.Form {
-	return conv.withInitStmt(node.Init, conv.switchStmt(node))
+	t := conv.pushTarget(false)
+	var form sexp.Form
+	if needsCaseLabels(node.Body) {
+		form = conv.switchStmtLabeled(node, t)
+	} else {
+		form = conv.switchStmt(node)
+	}
+	return conv.popTarget(conv.withInitStmt(node.Init, form))
 }
 
 func (conv *converter) switchStmt(node *ast.SwitchStmt) sexp.Form {
@@ -40,3 +52,239 @@ func (conv *converter) switchStmt(node *ast.SwitchStmt) sexp.Form {
 		SwitchBody: body,
 	}
 }
+
+// Local that holds tag of switch that is converted by switchStmtLabeled.
+const switchTag = "_sw"
+
+// needsCaseLabels reports whether switch clause bodies
+// can not be duplicated or reordered freely.
+func needsCaseLabels(body *ast.BlockStmt) bool {
+	for _, cc := range body.List {
+		cc := cc.(*ast.CaseClause)
+		if len(cc.List) > 1 || endsWithFallthrough(cc) {
+			return true
+		}
+	}
+	return false
+}
+
+func endsWithFallthrough(cc *ast.CaseClause) bool {
+	if len(cc.Body) == 0 {
+		return false
+	}
+	branch, ok := cc.Body[len(cc.Body)-1].(*ast.BranchStmt)
+	return ok && branch.Tok == token.FALLTHROUGH
+}
+
+// switchStmtLabeled converts switch with "fallthrough" statements
+// or multi-value case clauses.
+// Every clause body is converted exactly once;
+// clause with multiple values tests them in order.
+// "fallthrough" jumps to the label at the start of the next clause body.
+func (conv *converter) switchStmtLabeled(node *ast.SwitchStmt, t *branchTarget) sexp.Form {
+	var forms []sexp.Form
+	var tag sexp.Local
+	if node.Tag != nil {
+		typ := conv.typeOf(node.Tag)
+		tag = sexp.Local{Name: switchTag, Typ: typ}
+		forms = append(forms, &sexp.Bind{Name: switchTag, Init: conv.Expr(node.Tag)})
+	}
+
+	defaultBody := sexp.EmptyBlock
+	clauses := make([]sexp.CaseClause, 0, len(node.Body.List))
+	fallthroughTarget := false
+	for i, cc := range node.Body.List {
+		cc := cc.(*ast.CaseClause)
+
+		var body []sexp.Form
+		if fallthroughTarget {
+			body = append(body, &sexp.Label{Name: t.caseLabel(i)})
+		}
+		fallthroughTarget = endsWithFallthrough(cc)
+		if fallthroughTarget {
+			t.fallthroughLabel = t.caseLabel(i + 1)
+		}
+		body = append(body, conv.stmtList(cc.Body)...)
+		t.fallthroughLabel = ""
+
+		if cc.List == nil {
+			defaultBody = sexp.Block(body)
+			continue
+		}
+		var cond sexp.Form
+		for _, caseExpr := range cc.List {
+			test := conv.caseCond(tag, caseExpr)
+			if cond == nil {
+				cond = test
+			} else {
+				cond = &sexp.Or{X: cond, Y: test}
+			}
+		}
+		clauses = append(clauses, sexp.CaseClause{Expr: cond, Body: sexp.Block(body)})
+	}
+
+	forms = append(forms, &sexp.SwitchTrue{SwitchBody: sexp.SwitchBody{
+		Clauses:     clauses,
+		DefaultBody: defaultBody,
+	}})
+	return sexp.Block(forms)
+}
+
+// caseCond returns a test for the switch case value.
+// Tag is not bound for switches without tag expression.
+func (conv *converter) caseCond(tag sexp.Local, node ast.Expr) sexp.Form {
+	if tag.Typ == nil {
+		return conv.Expr(node)
+	}
+	if conv.info.Types[node].IsNil() {
+		return sexp.NewLispCall(lisp.FnEq, tag, nilValue(tag.Typ))
+	}
+	conv.ctxType = tag.Typ
+	return conv.equality(tag, conv.copyValue(conv.Expr(node), tag.Typ), tag.Typ)
+}
+
+func (conv *converter) TypeSwitchStmt(node *ast.TypeSwitchStmt) sexp.Form {
+	conv.pushTarget(false)
+	return conv.popTarget(conv.withInitStmt(node.Init, conv.typeSwitchStmt(node)))
+}
+
+// Locals that hold type switch guard expression and its type tag.
+const (
+	typeSwitchVal = "_tsv"
+	typeSwitchTag = "_tst"
+)
+
+func (conv *converter) typeSwitchStmt(node *ast.TypeSwitchStmt) sexp.Form {
+	var assert *ast.TypeAssertExpr
+	switch stmt := node.Assign.(type) {
+	case *ast.ExprStmt:
+		assert = stmt.X.(*ast.TypeAssertExpr)
+	case *ast.AssignStmt:
+		assert = stmt.Rhs[0].(*ast.TypeAssertExpr)
+	}
+
+	typ := conv.typeOf(assert.X)
+	val := sexp.Local{Name: typeSwitchVal, Typ: typ}
+	forms := []sexp.Form{
+		&sexp.Bind{Name: typeSwitchVal, Init: conv.Expr(assert.X)},
+	}
+	if !isRawIface(typ) {
+		forms = append(forms, &sexp.Bind{
+			Name: typeSwitchTag,
+			Init: sexp.NewCall(rt.FnIfaceTag, val),
+		})
+	}
+
+	defaultBody := sexp.EmptyBlock
+	clauses := make([]sexp.CaseClause, 0, len(node.Body.List))
+	for _, cc := range node.Body.List {
+		cc := cc.(*ast.CaseClause)
+		body := conv.typeCaseBody(cc, val)
+		if cc.List == nil {
+			defaultBody = body
+			continue
+		}
+		var cond sexp.Form
+		for _, caseExpr := range cc.List {
+			test := conv.typeCaseCond(val, caseExpr)
+			if cond == nil {
+				cond = test
+			} else {
+				cond = &sexp.Or{X: cond, Y: test}
+			}
+		}
+		clauses = append(clauses, sexp.CaseClause{Expr: cond, Body: body})
+	}
+
+	forms = append(forms, &sexp.SwitchTrue{SwitchBody: sexp.SwitchBody{
+		Clauses:     clauses,
+		DefaultBody: defaultBody,
+	}})
+	return sexp.Block(forms)
+}
+
+// typeCaseBody converts case clause body.
+// If type switch introduces a variable, it is bound
+// to a value of the clause type.
+func (conv *converter) typeCaseBody(cc *ast.CaseClause, val sexp.Local) sexp.Block {
+	body := conv.stmtList(cc.Body)
+	obj, ok := conv.info.Implicits[cc].(*types.Var)
+	if !ok {
+		return sexp.Block(body)
+	}
+
+	var init sexp.Form = val
+	if len(cc.List) == 1 {
+		init = conv.typeCaseValue(val, obj.Type())
+	}
+	if conv.boxed[obj] {
+		init = box(init)
+	}
+	bind := &sexp.Bind{Name: obj.Name(), Init: init}
+	return sexp.Block(append([]sexp.Form{bind}, body...))
+}
+
+// typeCaseValue converts val to the type of single-type case clause.
+func (conv *converter) typeCaseValue(val sexp.Local, typ types.Type) sexp.Form {
+	switch {
+	case types.Identical(typ, val.Typ) || isRawIface(typ),
+		isRawIface(val.Typ) && !types.IsInterface(typ) && !isTaggedType(typ):
+		return &sexp.TypeCast{Form: val, Typ: typ}
+	case types.IsInterface(typ):
+		return &sexp.TypeCast{
+			Form: conv.call(rt.FnConvIface, val, conv.ifaceDesc(typ)),
+			Typ:  typ,
+		}
+	default:
+		return &sexp.TypeCast{Form: sexp.NewLispCall(lisp.FnCdr, val), Typ: typ}
+	}
+}
+
+// typeCaseCond returns a test that checks val dynamic type.
+func (conv *converter) typeCaseCond(val sexp.Local, node ast.Expr) sexp.Form {
+	if isNilIdent(conv, node) {
+		isNil := sexp.NewLispCall(lisp.FnEq, val, nilInterface)
+		if isRawIface(val.Typ) {
+			return &sexp.Or{X: sexp.NewNot(val), Y: isNil}
+		}
+		return isNil
+	}
+
+	typ := conv.typeOf(node)
+	switch {
+	case isRawIface(val.Typ):
+		// Nil interface is Lisp nil, which also satisfies
+		// "booleanp" and "symbolp"; it matches only "case nil".
+		if pred := rawTypePred(typ); pred != nil {
+			if pred == lisp.FnIsBool || pred == lisp.FnIsSymbol {
+				return &sexp.And{X: val, Y: sexp.NewLispCall(pred, val)}
+			}
+			return sexp.NewLispCall(pred, val)
+		}
+		if isRawIface(typ) {
+			return &sexp.And{X: val, Y: sexp.Bool(true)}
+		}
+		if types.IsInterface(typ) {
+			return conv.call(rt.FnImplementsRawIface, val, conv.ifaceDesc(typ))
+		}
+		if isTaggedType(typ) {
+			return sexp.NewLispCall(
+				lisp.FnEq,
+				conv.call(rt.FnRawIfaceTag, val),
+				conv.typeTag(typ, node),
+			)
+		}
+		panic(exn.Conv(conv.fileSet, "unsupported type switch case", node))
+	case types.Identical(typ, val.Typ):
+		// Any non-nil value matches.
+		return sexp.NewNot(sexp.NewLispCall(lisp.FnEq, val, nilInterface))
+	case types.IsInterface(typ):
+		return conv.call(rt.FnImplementsIface, val, conv.ifaceDesc(typ))
+	default:
+		return sexp.NewLispCall(
+			lisp.FnEq,
+			sexp.Local{Name: typeSwitchTag, Typ: lisp.TypObject},
+			conv.typeTag(typ, node),
+		)
+	}
+}
@@ -0,0 +1,172 @@
+package sexpconv
+
+import (
+	"exn"
+	"go/ast"
+	"go/types"
+	"magic_pkg/emacs/lisp"
+	"magic_pkg/emacs/rt"
+	"sexp"
+	"tu/symbols"
+	"xtypes"
+)
+
+func (conv *converter) TypeAssertExpr(node *ast.TypeAssertExpr) sexp.Form {
+	x := conv.Expr(node.X)
+	xTyp := conv.typeOf(node.X)
+	typ := conv.typeOf(node.Type)
+
+	var form sexp.Form
+	switch {
+	case types.Identical(xTyp, typ):
+		return x
+	case isRawIface(xTyp):
+		if isRawIface(typ) && rawTypePred(typ) == nil {
+			form = x
+		} else if types.IsInterface(typ) {
+			form = conv.call(rt.FnAssertRawIface, x, conv.ifaceDesc(typ))
+		} else if isTaggedType(typ) {
+			form = conv.call(rt.FnAssertRawType, x, conv.typeTag(typ, node))
+		} else {
+			form = conv.call(conv.coerceFunc(typ, node), x)
+		}
+	case types.IsInterface(typ):
+		form = conv.call(rt.FnAssertIface, x, conv.ifaceDesc(typ))
+	default:
+		form = conv.call(rt.FnAssertType, x, conv.typeTag(typ, node))
+	}
+	return &sexp.TypeCast{Form: form, Typ: typ}
+}
+
+// typeAssertOk converts "comma, ok" form of type assertion.
+// Returned call packs both results.
+func (conv *converter) typeAssertOk(node *ast.TypeAssertExpr) (sexp.Form, *types.Tuple) {
+	x := conv.Expr(node.X)
+	xTyp := conv.typeOf(node.X)
+	typ := conv.typeOf(node.Type)
+
+	var call *sexp.Call
+	switch {
+	case isRawIface(typ) && rawTypePred(typ) == nil:
+		// Non-nil lisp values and non-nil interface values match.
+		pred := sexp.Symbol{Val: "identity"}
+		if !isRawIface(xTyp) {
+			pred = sexp.Symbol{Val: "consp"}
+		}
+		call = conv.call(rt.FnAssertPredOk, x, pred, ZeroValue(typ))
+	case isRawIface(xTyp) && types.IsInterface(typ):
+		call = conv.call(rt.FnAssertRawIfaceOk, x, conv.ifaceDesc(typ))
+	case isRawIface(xTyp) && isTaggedType(typ):
+		call = conv.call(rt.FnAssertRawTypeOk, x, conv.typeTag(typ, node), ZeroValue(typ))
+	case isRawIface(xTyp):
+		pred := rawTypePred(typ)
+		if pred == nil {
+			panic(exn.Conv(conv.fileSet, "unsupported type assertion", node))
+		}
+		call = conv.call(rt.FnAssertPredOk, x, sexp.Symbol{Val: pred.Name}, ZeroValue(typ))
+	case types.IsInterface(typ):
+		call = conv.call(rt.FnAssertIfaceOk, x, conv.ifaceDesc(typ))
+	default:
+		call = conv.call(rt.FnAssertTypeOk, x, conv.typeTag(typ, node), ZeroValue(typ))
+	}
+
+	return call, okTuple(typ)
+}
+
+// typeTag returns dynamic type tag for named type.
+// Types of imported packages are permitted: generic instances
+// and type arguments often refer to them.
+func (conv *converter) typeTag(typ types.Type, node ast.Node) sexp.Form {
+	named := xtypes.AsNamedType(typ)
+	if named == nil || named.Obj().Pkg() == nil {
+		panic(exn.Conv(conv.fileSet, "dynamic type tag is unavailable", node))
+	}
+	pkgName := conv.pkgFullName(named.Obj().Pkg())
+	_, ptr := typ.(*types.Pointer)
+	return sexp.Symbol{Val: symbols.TypeTag(pkgName, symbols.NamedTypeName(named), ptr)}
+}
+
+// ifaceDesc returns interface descriptor that is used
+// to build itabs during run time.
+func (conv *converter) ifaceDesc(typ types.Type) sexp.Form {
+	return sexp.Var{
+		Name: conv.itabEnv.InternIface(typ.(*types.Named)),
+		Typ:  lisp.TypObject,
+	}
+}
+
+func (conv *converter) coerceFunc(typ types.Type, node ast.Node) *sexp.Func {
+	switch rawTypePred(typ) {
+	case lisp.FnIsBool:
+		return rt.FnCoerceBool
+	case lisp.FnIsInt:
+		return rt.FnCoerceInt
+	case lisp.FnIsFloat:
+		return rt.FnCoerceFloat
+	case lisp.FnIsStr:
+		return rt.FnCoerceString
+	case lisp.FnIsSymbol:
+		return rt.FnCoerceSymbol
+	}
+	panic(exn.Conv(conv.fileSet, "unsupported type assertion", node))
+}
+
+// rawTypePred returns Emacs Lisp predicate that checks
+// if raw interface value has given type.
+// Returns nil for types that can not be checked.
+func rawTypePred(typ types.Type) *lisp.Func {
+	if types.Identical(typ, lisp.TypSymbol) {
+		return lisp.FnIsSymbol
+	}
+	if typ, ok := typ.(*types.Basic); ok {
+		switch {
+		case typ.Kind() == types.Bool:
+			return lisp.FnIsBool
+		case typ.Kind() == types.String:
+			return lisp.FnIsStr
+		case typ.Info()&types.IsInteger != 0:
+			return lisp.FnIsInt
+		case typ.Info()&types.IsFloat != 0:
+			return lisp.FnIsFloat
+		}
+	}
+	return nil
+}
+
+// isRawIface reports whether values of typ are stored without
+// interface wrapper (lisp.Object and "interface{}").
+func isRawIface(typ types.Type) bool {
+	if named, ok := typ.(*types.Named); ok {
+		return named.Obj().Pkg() == lisp.Package && types.IsInterface(typ)
+	}
+	return xtypes.IsEmptyInterface(typ)
+}
+
+// isTaggedType reports whether values of typ carry dynamic
+// type tag when they are stored in "interface{}".
+// Named types that can not be recognized by Emacs Lisp
+// type predicates (and pointers to them) are tagged.
+func isTaggedType(typ types.Type) bool {
+	named := xtypes.AsNamedType(typ)
+	if named == nil || named.Obj().Pkg() == nil || named.Obj().Pkg() == lisp.Package {
+		return false
+	}
+	if _, ok := typ.(*types.Pointer); ok {
+		return true
+	}
+	switch named.Underlying().(type) {
+	case *types.Basic, *types.Interface:
+		return false
+	default:
+		return true
+	}
+}
+
+func isNilIdent(conv *converter, node ast.Expr) bool {
+	ident, ok := node.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	_, ok = conv.info.Uses[ident].(*types.Nil)
+	return ok
+}
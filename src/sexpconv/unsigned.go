@@ -1,17 +1,22 @@
 package sexpconv
 
 import (
-	"exn"
 	"go/types"
+	"magic_pkg/emacs/rt"
 	"sexp"
 )
 
 // Given a form and desired type,
 // returns either passed form unchanged or
 // unsigned expression with correct overflow bits truncation.
-func uintElem(form sexp.Form, dstTyp types.Type) sexp.Form {
+//
+// In wraparound mode values are always truncated,
+// so form is returned unchanged.
+// 64-bit unsigned types are truncated by run time function,
+// because their mask does not fit into fixnum.
+func (conv *converter) uintElem(form sexp.Form, dstTyp types.Type) sexp.Form {
 	typ, ok := dstTyp.(*types.Basic)
-	if !ok {
+	if !ok || conv.pkg.Wraparound {
 		return form
 	}
 
@@ -20,11 +25,10 @@ func uintElem(form sexp.Form, dstTyp types.Type) sexp.Form {
 		return sexp.NewBitAnd(form, sexp.Int(0xFF))
 	case types.Uint16:
 		return sexp.NewBitAnd(form, sexp.Int(0xFFFF))
-	case types.Uint32, types.Uint:
-		return sexp.NewBitAnd(form, sexp.Int(0xFFFFFF))
-
-	case types.Uint64:
-		panic(exn.NoImpl("uint64 coercion in array/slice context"))
+	case types.Uint32:
+		return sexp.NewBitAnd(form, sexp.Int(0xFFFFFFFF))
+	case types.Uint, types.Uint64, types.Uintptr:
+		return &sexp.TypeCast{Form: sexp.NewCall(rt.FnWrapUint64, form), Typ: typ}
 
 	default:
 		return form
@@ -27,6 +27,9 @@ func isStructLit(form sexp.Form) bool {
 }
 
 func isArrayLit(form sexp.Form) bool {
+	if cast, ok := form.(*sexp.TypeCast); ok {
+		form = cast.Form // Named array literal
+	}
 	_, ok := form.(*sexp.ArrayLit)
 	if ok {
 		return true
@@ -41,3 +44,13 @@ func toFormList(form sexp.Form) sexp.FormList {
 	}
 	return sexp.FormList([]sexp.Form{form})
 }
+
+func unparen(node ast.Expr) ast.Expr {
+	for {
+		paren, ok := node.(*ast.ParenExpr)
+		if !ok {
+			return node
+		}
+		node = paren.X
+	}
+}
@@ -0,0 +1,45 @@
+package sexpconv
+
+import (
+	"go/types"
+	"magic_pkg/emacs/rt"
+	"sexp"
+	"strconv"
+)
+
+// VariadicEntry creates exported entry point for variadic function.
+// Entry takes "&rest" argument and passes it to fn as a slice.
+// Entry gets fn name and documentation; caller is expected
+// to rename fn, so Go code calls it directly.
+func VariadicEntry(fn *sexp.Func, sig *types.Signature) *sexp.Func {
+	entry := &sexp.Func{
+		Name:      fn.Name,
+		Params:    make([]string, sig.Params().Len()),
+		Variadic:  true,
+		Results:   fn.Results,
+		DocString: fn.DocString,
+	}
+	args := make([]sexp.Form, len(entry.Params))
+	for i := range entry.Params {
+		// Parameters may be unnamed or blank.
+		entry.Params[i] = sig.Params().At(i).Name()
+		if entry.Params[i] == "" || entry.Params[i] == blankIdent {
+			entry.Params[i] = "arg" + strconv.Itoa(i)
+		}
+		args[i] = sexp.Local{Name: entry.Params[i], Typ: sig.Params().At(i).Type()}
+	}
+	last := len(args) - 1
+	args[last] = &sexp.TypeCast{
+		Form: sexp.NewCall(rt.FnSeqToSlice, args[last]),
+		Typ:  sig.Params().At(last).Type(),
+	}
+
+	call := &sexp.Call{Fn: fn, Args: args}
+	if fn.Results.Len() == 0 {
+		entry.Body = sexp.Block{&sexp.ExprStmt{Expr: call}, &sexp.Return{}}
+	} else {
+		// Several results are forwarded packed.
+		entry.Body = sexp.Block{&sexp.Return{Results: []sexp.Form{call}}}
+	}
+	return entry
+}
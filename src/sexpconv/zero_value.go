@@ -33,7 +33,7 @@ func ZeroValue(typ types.Type) sexp.Form {
 		return sexp.Nil
 	}
 
-	switch typ := typ.(type) {
+	switch typ := types.Unalias(typ).(type) {
 	case *types.Basic:
 		return basicTypeZeroValue(typ)
 
@@ -47,6 +47,22 @@ func ZeroValue(typ types.Type) sexp.Form {
 	case *types.Map:
 		return nilMap
 
+	case *types.Slice:
+		return nilSlice
+
+	case *types.Signature:
+		return nilFunc
+
+	case *types.Pointer, *types.Chan:
+		return sexp.Nil
+
+	case *types.Interface:
+		// "interface{}" values are stored as is.
+		if typ.Empty() {
+			return sexp.Nil
+		}
+		return nilInterface
+
 	case *types.Named:
 		utyp := typ.Underlying()
 		if structTyp, ok := utyp.(*types.Struct); ok {
@@ -57,14 +73,40 @@ func ZeroValue(typ types.Type) sexp.Form {
 			return &sexp.StructLit{Vals: vals, Typ: typ}
 		}
 		if _, ok := utyp.(*types.Interface); ok {
+			if isRawIface(typ) {
+				return sexp.Nil
+			}
 			return nilInterface
 		}
-		return basicTypeZeroValue(utyp.(*types.Basic))
+		return ZeroValue(utyp)
 	}
 
 	panic(exn.NoImpl("can not provide zero value for %#v", typ))
 }
 
+// nilValue returns nil value of given type.
+// Returns nil if type has no nil value.
+func nilValue(typ types.Type) sexp.Form {
+	switch typ.Underlying().(type) {
+	case *types.Map:
+		return nilMap
+	case *types.Slice:
+		return nilSlice
+	case *types.Signature:
+		return nilFunc
+	case *types.Pointer, *types.Chan:
+		return sexp.Nil
+	case *types.Interface:
+		// Like in ZeroValue, raw interface values are nil.
+		if isRawIface(typ) {
+			return sexp.Nil
+		}
+		return nilInterface
+	default:
+		return nil
+	}
+}
+
 // Nil values
 var (
 	// #REFS: #74.
@@ -72,8 +114,11 @@ var (
 		Name: "goism-rt.NilMap",
 		Typ:  types.NewMap(lisp.TypObject, lisp.TypObject),
 	}
+	nilSlice = sexp.Var{
+		Name: "goism-rt.NilSlice",
+		Typ:  types.NewSlice(lisp.TypObject),
+	}
 
 	nilFunc      = sexp.Symbol{Val: "goism-rt.NilFunction"}
 	nilInterface = sexp.Symbol{Val: "goism-rt.NilInterface"}
-	nilSlice     = sexp.Symbol{Val: "goism-rt.NilSlice"}
 )
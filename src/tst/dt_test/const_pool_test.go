@@ -57,6 +57,16 @@ func TestConstPoolBytes(t *testing.T) {
 		t.Errorf("%s != %s", string(result), string(expected))
 	}
 
+	cvec.Clear()
+	cvec.InsertFloat(2.0)
+	cvec.InsertFloat(1e21)
+
+	result = cvec.Bytes()
+	expected = []byte(`[2.0 1e+21 ]`)
+	if !bytes.Equal(result, expected) {
+		t.Errorf("%s != %s", string(result), string(expected))
+	}
+
 	cvec.Clear()
 
 	result = cvec.Bytes()
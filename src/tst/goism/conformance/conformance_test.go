@@ -6,22 +6,44 @@ import (
 )
 
 func init() {
+	goism.LoadPackage("time")
+	goism.LoadPackage("errors")
+	goism.LoadPackage("conformance/initdep")
 	goism.LoadPackage("conformance")
 }
 
 func Test1Ops(t *testing.T) {
 	testCalls(t, goism.CallTests{
 		// Int ops.
-		"add1Int 1":     "2",
-		"addInt 1 2 3":  "6",
-		"sub1Int 1":     "0",
-		"subInt 3 2 1":  "0",
-		"mulInt 2 2 20": "80",
-		"quoInt 20 2 2": "5",
-		"gtInt 2 1":     "t",
-		"ltInt 2 1":     "nil",
-		"incInt 2":      "3",
-		"decInt 2":      "1",
+		"add1Int 1":            "2",
+		"addInt 1 2 3":         "6",
+		"sub1Int 1":            "0",
+		"subInt 3 2 1":         "0",
+		"mulInt 2 2 20":        "80",
+		"quoInt 20 2 2":        "5",
+		"gtInt 2 1":            "t",
+		"ltInt 2 1":            "nil",
+		"incInt 2":             "3",
+		"decInt 2":             "1",
+		"quoInt -7 2 1":        "-3",
+		"remInt 7 3":           "1",
+		"remInt -7 3":          "-1",
+		"remInt 7 -3":          "1",
+		"andInt 12 10":         "8",
+		"orInt 12 10":          "14",
+		"xorInt 12 10":         "6",
+		"andNotInt 12 10":      "4",
+		"notInt 5":             "-6",
+		"shlInt 3 4":           "48",
+		"shrInt -16 2":         "-4",
+		"notUint8 5":           "250",
+		"notUint 5":            "18446744073709551610",
+		"notUint64 0":          "18446744073709551615",
+		"opAssignInt 5":        "16",
+		"quoZero 7 7":          "2",
+		"quoZero 7 0":          "0",
+		"testQuoZeroValue 7 0": `"integer divide by zero"`,
+		"testQuoZeroValue 7 2": `"no panic"`,
 		// Float ops.
 		"add1Float 1.0":         "2.0",
 		"addFloat 1.1 2.2 3.3":  "6.6",
@@ -183,7 +205,26 @@ func Test9For(t *testing.T) {
 
 func Test10Range(t *testing.T) {
 	testCalls(t, goism.CallTests{
-		"sumArray1": "6",
+		"sumArray1":                "6",
+		"sumArray2":                "6",
+		"sumArrayKeyVal":           "8",
+		"rangeArrayAssign":         "27",
+		"rangeKeyMutation":         "3",
+		"testRangeSlice 10":        "10",
+		"testRangeArrayCopy":       "630",
+		"testRangeSliceKeyVal":     "40",
+		"testRangeSliceBreak":      "3",
+		"testRangeNested":          "90",
+		`testRangeString "aλb"`:    `"0:a;1:λ;3:b;"`,
+		`testRangeStringKeys "λλ"`: "2",
+		`testRangeStringKeys "ab"`: "1",
+		"testRangeMap 10":          "55",
+		"testRangeMapKeys":         "2",
+		"testRangeMapDelete 5":     "1",
+		"testRangeMapUpdate 3":     "21",
+		"testRangeLispList":        "8",
+		"testRangeLispVector":      "6",
+		"testLispSeqSlice":         "6",
 	})
 }
 
@@ -194,6 +235,14 @@ func Test11Maps(t *testing.T) {
 		"testMapUpdate 10":    "10",
 		"testMapDelete 10":    "10",
 		"testMapLen 10":       "10",
+		"testMapLit":          "331",
+		"testMapLitEmpty":     "6",
+		"testMapLitNested":    "23",
+		"testMapLitStruct":    `"alphabeta"`,
+		"testMapLitPtr":       "8",
+		"testMapCommaOk":      `"abc"`,
+		"testMapCommaOkNil":   `"ok"`,
+		"testMapNilDelete":    "0",
 	})
 }
 
@@ -228,6 +277,351 @@ func Test12Strings(t *testing.T) {
 	})
 }
 
+func Test13Closures(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testClosureCall 10":          "10",
+		"testClosureCounter 10":       "10",
+		"testClosureShared 10":        "10",
+		"testClosureParamMutation 10": "10",
+		"testClosureNested 10":        "10",
+		"testClosureAdder 10":         "10",
+		"testFuncValue 10":            "10",
+		"testClosureIIFE 10":          "10",
+		"testClosureMultiResult 10":   "10",
+		"testClosureGlobal 10":        "10",
+		"testClosureMapcar 10":        "10",
+		"testClosureIgnoredCall":      "14",
+	})
+}
+
+func Test14Defer(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testDeferOrder":          `"cba"`,
+		"testDeferArgs":           `"yx"`,
+		"testDeferEarlyReturn 3":  "3",
+		"testDeferEarlyReturn 10": "5",
+		"testDeferClosure 10":     "10",
+		"testRecoverValue":        `"boom"`,
+		"testRecoverZero 10":      "0",
+		"testRecoverNested":       `"p"`,
+		"testRecoverReplaced":     `"basecond"`,
+		"testRecoverNoPanic":      "t",
+	})
+}
+
+func Test15TypeSwitch(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testTypeSwitchIface":            `"squarerectothernil"`,
+		"testTypeSwitchNilIface":         `"default,default,bool,symbol,any"`,
+		"testTypeSwitchBind 10":          "10",
+		"testTypeSwitchMulti 10":         "10",
+		"testTypeSwitchLispInt 10":       `"int10"`,
+		`testTypeSwitchLispString "foo"`: `"string:foo"`,
+		"testTypeSwitchLispOther":        `"symbolfloatobject"`,
+		"testTypeSwitchLispSymbol":       "t",
+		"testTypeSwitchPtrTag":           `"valueptr"`,
+		"testTypeSwitchEmptyIface":       `"int,badge:a,*badge:b,square,shape,other"`,
+		"testTypeAssertEmptyIface 2":     "6",
+		"testTypeSwitchNamedSlice":       "2",
+	})
+}
+
+func Test16TypeAssert(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testTypeAssertOk 10":        "10",
+		"testTypeAssertOkFail":       "t",
+		"testTypeAssertOkNil":        "t",
+		"testTypeAssert 10":          "10",
+		"testTypeAssertIface 10":     "10",
+		"testTypeAssertIfaceMethod":  `"file"`,
+		"testIfaceConv 10":           "10",
+		"testTypeAssertLispOk 10":    "10",
+		"testTypeAssertLispOkFail":   "t",
+		"testTypeAssertSignature 10": "9",
+	})
+}
+
+func Test17Chan(t *testing.T) {
+	requireThreads(t)
+	testCalls(t, goism.CallTests{
+		"testChanBuffered 10":      "10",
+		"testChanUnbuffered 10":    "10",
+		"testChanRange 10":         "55",
+		"testChanRangeBuffered 10": "55",
+		"testChanPingPong 10":      "10",
+		"testChanClosedRecv":       "t",
+		"testChanLenCap":           "13",
+		"testChanUnbufferedLenCap": "0",
+		"testChanCloseTwice":       `"close of closed channel"`,
+		"testChanSendClosed 10":    `"send on closed channel"`,
+	})
+}
+
+func Test18Select(t *testing.T) {
+	requireThreads(t)
+	testCalls(t, goism.CallTests{
+		"testSelectDefault":         `"default"`,
+		"testSelectRecv 10":         "10",
+		"testSelectSend 10":         "10",
+		"testSelectBlocking 10":     "10",
+		"testSelectSendToRecv 10":   "10",
+		"testSelectSendToSelect 10": "10",
+		"testSelectClosed":          "t",
+		"testSelectNilChan 10":      "10",
+		"testSelectFairness":        "t",
+		"testSelectTimeout":         `"timeout"`,
+		"testSelectSendClosed 10":   `"send on closed channel"`,
+		"testSelectEvalOrder":       `"a1bc2"`,
+	})
+}
+
+func Test19Init(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testInitOrder":        `"vwv012"`,
+		"testInitImportedOnce": "11",
+	})
+}
+
+func Test20MethodValues(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testMethodValue 10":        "20",
+		"testMethodValueCopy 10":    "10",
+		"testMethodValueAddr 10":    "10",
+		"testMethodExpr 10":         "20",
+		"testMethodExprValue 10":    "10",
+		"testMethodValueIface 10":   "100",
+		"testMethodValueFuncall 10": "10",
+	})
+}
+
+func Test21Embed(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testEmbedField 10":             "21",
+		"testEmbedFieldDeep 10":         `"deep20"`,
+		"testEmbedMethod 10":            "20",
+		"testEmbedPtrField 10":          "11",
+		"testEmbedPtrMethod 10":         "10",
+		"testEmbedIfaceCall 10":         "100",
+		"testEmbedMethodValue 10":       "10",
+		"testEmbedMethodExpr 10":        "10",
+		"testEmbedEmbeddedIface 10":     "200",
+		"testEmbedEmbeddedIfaceItab 10": "100",
+	})
+}
+
+func Test22Pointers(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testPtrLocal 10":          "20",
+		`testPtrOutParam "abc"`:    "3",
+		`testPtrOutParam ""`:       "-1",
+		"testPtrSwap":              `"ba"`,
+		"testPtrArrayElem 10":      "20",
+		"testPtrSliceElem 10":      "10",
+		"testPtrFields 10":         "70",
+		"testPtrGlobal 10":         "10",
+		"testPtrCaptured 10":       "11",
+		"testPtrNew 10":            "20",
+		"testPtrStructAssign 10":   "20",
+		"testPtrArray 10":          "20",
+		"testPtrVarAssign 10":      "10",
+		"testPtrNestedAssign 10":   "20",
+		"testPtrArrayVarAssign 10": "20",
+		"testPtrParallelAssign":    "21",
+		"testPtrMethod":            "2",
+		"testPtrNil":               "t",
+		"testPtrEq":                "t",
+	})
+}
+
+func Test23Lvalues(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testIncField 10":         "21",
+		"testIncArray 10":         "10",
+		"testIncSlice 10":         "10",
+		"testIncStructElem 10":    "11",
+		"testIncMap":              "25",
+		"testIncNamedMap":         "1",
+		"testIncPtr 10":           "22",
+		"testIncOnce":             `"ii4"`,
+		"testSwapVars":            "21",
+		"testParallelAssign":      "911",
+		"testParallelAssignIface": `"avalue"`,
+		"testMiddleFieldStore 5":  "1050322",
+	})
+}
+
+func Test24NamedResults(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testNamedZero":           `"ok"`,
+		"testNamedNaked 10":       "20",
+		"testNamedNaked -1":       "-1",
+		"testNamedExplicit 10":    "10",
+		"testNamedSwap":           `"ba"`,
+		"testNamedDeferModify 10": "20",
+		"testNamedDeferRead 5":    "5",
+		"testNamedRecover 10":     "10",
+		"testNamedRecover 20":     "-20",
+		"testNamedClosure":        "2",
+	})
+}
+
+func Test25Variadic(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testVariadicCall":         "10",
+		"testVariadicSpread":       "6",
+		"testVariadicSpreadAlias":  "100",
+		"testVariadicMixed":        `"a-b-cx,y"`,
+		"testVariadicMethod":       "10",
+		"testVariadicFuncValue":    "13",
+		"testVariadicExported":     `"ab,c"`,
+		`VariadicJoin "-" "a" "b"`: `"a-b"`,
+		`VariadicJoin ","`:         `""`,
+		"testAppendMulti":          "8",
+		"testAppendSlice":          "55",
+		"testAppendSliceInPlace":   "11",
+		"testAppendSelf":           "63",
+		"testAppendString":         `"abcd"`,
+		"testLispSpread":           `"abc"`,
+		"testLispSpreadFFI":        "1",
+	})
+}
+
+func Test26Equality(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testEqStruct":         `"ab"`,
+		"testEqStructPtrField": `"ab"`,
+		"testEqStructMixed":    `"ab"`,
+		"testEqArray":          `"ab"`,
+		"testEqIface":          `"abcd"`,
+		"testEqEmptyIface":     `"abc"`,
+		"testEqNilRaw":         `"abc"`,
+		"testEqBoolString":     `"ab"`,
+		"testMapStructKey":     "215",
+		"testMapMixedKey":      "2011",
+		"testMapArrayKey":      "22",
+		"testMapFloatKey":      "12",
+		"testMapPtrKey":        "21",
+		"testMapIfaceKey":      "211",
+		"testMapEmptyIfaceKey": "313",
+	})
+}
+
+func Test27Branch(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testBreakLabel 10":         "16",
+		"testContinueLabel 4":       "10",
+		"testContinueLabelRange":    `"ab;cef;"`,
+		"testContinueLabelLocals 6": "30",
+		"testBreakSwitch 10":        "35",
+		"testBreakLabeledSwitch 10": "3",
+		"testBreakTypeSwitch 1":     `"i.i."`,
+		`testBreakTypeSwitch "s"`:   `"s.s."`,
+		"testBreakSelect":           "4",
+		"testBreakRangeArray":       "2",
+		"testFallthrough":           `"01d23 1d23 23 3 d23"`,
+		"testFallthroughDefault 1":  `"dbig"`,
+		"testFallthroughDefault 20": `"big"`,
+		"testMultiCase":             "1120",
+	})
+}
+
+func Test28LocalDecl(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testLocalConst 2":      "36",
+		"testLocalConstTyped":   "1.5",
+		"testLocalStruct 3":     "8",
+		"testLocalAlias 1":      "2",
+		"testLocalTypeIdentity": `"ab"`,
+		"testLocalIfaceType 3":  "9",
+		"testLocalTypeSwitch":   `"kind"`,
+		"testLocalTypeBlocks":   `"abcd"`,
+	})
+}
+
+func Test29Conversion(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testConvStruct 1":            "122",
+		"testConvStructPtr 1":         "2",
+		"testConvNamed 1":             "4",
+		"testConvNamedString":         `"ac"`,
+		"testConvIntToFloat 3":        "0.75",
+		"testConvNamedFloat 1":        "1.5",
+		"testConvFloatToInt 2.7":      "2",
+		"testConvFloatToInt -2.7":     "-2",
+		"testConvFloatToUint8 300.5":  "44",
+		"testConvIntToUint8 -1":       "255",
+		"testConvIntToUint8 257":      "1",
+		"testConvIntToUint16 65537":   "1",
+		"testConvIntToUint64 -1":      "18446744073709551615",
+		"testConvUint64Elem -1":       "0",
+		"testConvRune 955":            `"λ"`,
+		"testConvRuneInvalid":         "\"\uFFFD\"",
+		`testConvRunes "aλb"`:         "34",
+		`testConvRunesToString "abc"`: `"aλc"`,
+		`testConvBytes "abc"`:         `"xbc"`,
+	})
+}
+
+func Test30IfaceCall(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testIfaceCallWide 2":         "38",
+		"testIfaceCallVariadic":       "137",
+		"testIfaceCallMultiResult":    "32",
+		"testIfaceCallVariadicString": `"a-b-c"`,
+		"testIfaceMethodValueWide":    "10",
+		"testIfaceCallArgs":           `"abc"`,
+		"testMethodIfaceArg":          `"-a-b"`,
+	})
+}
+
+func Test31MultiRet(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testMultiRet10 1":              "35",
+		"testMultiRetNamed9 3":          `"s"`,
+		"testMultiRetDefer9 10":         "20",
+		"testMultiRetForward 1":         "27",
+		"testMultiRetRecursive 10":      "55089",
+		"testMultiRetNested":            "557",
+		"testMultiRetDeferCalls 3":      "3018",
+		"testMultiRetGlobal":            "440",
+		"testMultiRetForwardIface 6":    "66",
+		"testMultiRetRecursiveVector 3": "60912",
+	})
+}
+
+func Test32Generic(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testGenericMap":            "123",
+		"testGenericFilter":         `"lispelisp"`,
+		"testGenericSum":            "6.75",
+		"testGenericIndex":          "19",
+		"testGenericFuncValue":      "9",
+		"testGenericNested":         "3",
+		"testGenericStruct":         `"x5"`,
+		"testGenericStack":          "2",
+		"testGenericIface":          "1",
+		"testGenericMethodIfaceArg": `"n1"`,
+	})
+}
+
+func Test33Errors(t *testing.T) {
+	testCalls(t, goism.CallTests{
+		"testErrorNil":          `"nil data zero assert iface"`,
+		"testErrorsNew":         `"anot found"`,
+		"testErrorfWrap":        `"read: open : not found"`,
+		"testErrorsAs":          `"open:not found"`,
+		"testErrorsIsMethod":    "t",
+		"testErrorfVerbs":       `"10|v|\"q\"|true| 1.50|ff|%|not found"`,
+		"testErrorfConcrete":    "t",
+		"testErrorfMulti":       `"not found, timeout"`,
+		"testErrorsUnwrapMulti": "t",
+		"testErrorRecover":      `"recovered: not found"`,
+		"testErrorTypeSwitch":   `"?[not found]s[stat f: not found]"`,
+		"testErrorMessage":      `"read: open : not found"`,
+		"testErrorCheck":        `"goism-error:Go error: \"not found\" user-error:not found"`,
+	})
+}
+
 func TestCombined(t *testing.T) {
 	testCalls(t, goism.CallTests{
 		"factorial 0": "1",
@@ -103,4 +103,7 @@ func TestPairwise(t *testing.T) {
 	testPairwise(t, testInfo{
 		Filename: "structs.go",
 	})
+	testPairwise(t, testInfo{
+		Filename: "integers.go",
+	})
 }
@@ -15,6 +15,14 @@ func testCalls(t *testing.T, table goism.CallTests) {
 	goism.RunTestCalls(pkg, t, table)
 }
 
+// requireThreads skips the test if Emacs daemon has no
+// threads support (Emacs 26+ is required).
+func requireThreads(t *testing.T) {
+	if goism.Eval("(fboundp 'make-thread)") != "t" {
+		t.Skip("Emacs daemon has no threads support")
+	}
+}
+
 // Enclose each passed string value into double quotes (unconditionnaly).
 func q(xs ...string) []string {
 	for i, x := range xs {
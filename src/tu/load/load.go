@@ -16,10 +16,12 @@ import (
 	"reflect"
 	"sexp"
 	"sexpconv"
+	"sort"
 	"strings"
 	"tu"
 	"tu/symbols"
 	"xast"
+	"xtypes"
 
 	"github.com/pkg/errors"
 )
@@ -30,28 +32,38 @@ type unit struct {
 	ins     *symbols.FuncTableInserter
 	itabEnv *symbols.ItabEnv
 	decls   map[*sexp.Func]funcDeclData
-	conv    *sexpconv.Converter
+	// Generic functions and methods; indexed by their symbols.
+	generics map[string]funcDeclData
+	conv     *sexpconv.Converter
+	// Package "init" functions in the file order.
+	inits map[*xast.Package][]*sexp.Func
 }
 
 type funcDeclData struct {
 	decl *ast.FuncDecl
 	pkg  *xast.Package
+	name string
+	sig  *types.Signature
+	doc  string // Only set for generic functions
 }
 
 type initData struct {
 	vars []string
-	init *sexp.Func
+	init *sexp.Func // Package initialization function
+	load *sexp.Func // Package load-time expression
 }
 
 func newUnit(ftab *symbols.FuncTable, masterPkg *types.Package, pkgPath string) *unit {
 	env := symbols.NewEnv(pkgPath)
 	itabEnv := symbols.NewItabEnv(masterPkg)
 	return &unit{
-		env:     env,
-		ins:     ftab.Inserter(),
-		itabEnv: itabEnv,
-		decls:   make(map[*sexp.Func]funcDeclData, 32),
-		conv:    sexpconv.NewConverter(ftab, env, itabEnv),
+		env:      env,
+		ins:      ftab.Inserter(),
+		itabEnv:  itabEnv,
+		decls:    make(map[*sexp.Func]funcDeclData, 32),
+		generics: make(map[string]funcDeclData),
+		conv:     sexpconv.NewConverter(ftab, env, itabEnv),
+		inits:    make(map[*xast.Package][]*sexp.Func),
 	}
 }
 
@@ -67,9 +79,8 @@ func Runtime() error {
 	collectFuncs(u)
 	rt.InitPackage(pkg.TypPkg)
 	rt.InitFuncs(ftab)
-	funcs := u.ins.GetAllFuncs()
-	convertFuncs(u, funcs, true)
-	opt.OptimizeFuncs(funcs)
+	convertFuncs(u, u.ins.GetAllFuncs(), true)
+	opt.OptimizeFuncs(u.ins.GetAllFuncs())
 	return nil
 }
 
@@ -89,18 +100,29 @@ func Package(pkgPath string, optimize bool) (*tu.Package, error) {
 	}
 
 	collectFuncs(u)
-	funcs := u.ins.GetAllFuncs()
-	convertFuncs(u, funcs, optimize)
+	convertFuncs(u, u.ins.GetAllFuncs(), optimize)
+	convertInstances(u, optimize)
 	if optimize {
-		opt.OptimizeFuncs(funcs)
+		// Refetch functions to include lambdas and instances.
+		opt.OptimizeFuncs(u.ins.GetAllFuncs())
 	}
 
 	initializers := collectInitializers(u, masterPkg)
+	// Global variables initializers may contain function literals
+	// and generic function references.
+	lambdas := u.conv.TakeLambdas()
+	for _, fn := range lambdas {
+		u.ins.Lambda(masterPkg.TypPkg, fn)
+	}
+	lambdas = append(lambdas, convertInstances(u, optimize)...)
+	if optimize {
+		opt.OptimizeFuncs(lambdas)
+	}
 
 	return &tu.Package{
 		Name:    masterPkg.AstPkg.Name,
-		Funcs:   u.ins.GetMasterFuncs(),
-		Init:    initializers.init,
+		Funcs:   append(u.ins.GetMasterFuncs(), initializers.init),
+		Init:    initializers.load,
 		Vars:    initializers.vars,
 		Comment: pkgComment(masterPkg.AstPkg.Files),
 	}, nil
@@ -108,21 +130,66 @@ func Package(pkgPath string, optimize bool) (*tu.Package, error) {
 
 func convertFuncs(u *unit, funcs []*sexp.Func, optimize bool) {
 	for _, fn := range funcs {
-		data := u.decls[fn]
+		data, ok := u.decls[fn]
+		if !ok {
+			continue // Generated function; already has a body
+		}
 		fn.Body = u.conv.FuncBody(&xast.Func{
 			Pkg:  data.pkg,
+			Name: data.name,
+			Sig:  data.sig,
 			Ret:  fn.Results,
 			Body: data.decl.Body,
 		})
 		if optimize && !fn.IsNoinline() && isInlineable(fn) {
 			fn.SetInlineable(true)
 		}
+		for _, lambda := range u.conv.TakeLambdas() {
+			u.ins.Lambda(data.pkg.TypPkg, lambda)
+		}
+	}
+}
+
+// convertInstances converts generic function instances that
+// were requested by converted functions.
+// Instances are defined inside master package.
+// Returns converted functions, including their lambdas.
+func convertInstances(u *unit, optimize bool) []*sexp.Func {
+	var funcs []*sexp.Func
+	masterPkg := u.conv.FuncTable().MasterPkg()
+	for insts := u.conv.TakeInstances(); len(insts) != 0; insts = u.conv.TakeInstances() {
+		for _, inst := range insts {
+			data := u.generics[inst.Generic]
+			tparams := data.sig.TypeParams()
+			if data.sig.Recv() != nil {
+				tparams = data.sig.RecvTypeParams()
+			}
+			inst.Fn.DocString = data.doc
+			u.ins.Lambda(masterPkg, inst.Fn)
+			inst.Fn.Body = u.conv.FuncBody(&xast.Func{
+				Pkg:        data.pkg,
+				Name:       symbols.InstanceName(data.name, inst.TypeArgs),
+				Sig:        data.sig,
+				Body:       data.decl.Body,
+				TypeParams: tparams,
+				TypeArgs:   inst.TypeArgs,
+			})
+			if optimize && isInlineable(inst.Fn) {
+				inst.Fn.SetInlineable(true)
+			}
+			funcs = append(funcs, inst.Fn)
+			for _, lambda := range u.conv.TakeLambdas() {
+				u.ins.Lambda(masterPkg, lambda)
+				funcs = append(funcs, lambda)
+			}
+		}
 	}
+	return funcs
 }
 
 func collectFuncs(u *unit) {
 	for _, p := range u.pkgs {
-		for _, f := range p.AstPkg.Files {
+		for _, f := range sortedFiles(p.AstPkg) {
 			for _, decl := range f.Decls {
 				if decl, ok := decl.(*ast.FuncDecl); ok {
 					collectFunc(u, p, decl)
@@ -130,6 +197,37 @@ func collectFuncs(u *unit) {
 			}
 		}
 	}
+	// Promoted methods refer to collected methods,
+	// so they are generated after all declarations are collected.
+	for _, p := range u.pkgs {
+		collectPromotedMethods(u, p)
+	}
+}
+
+// collectPromotedMethods generates functions for methods that
+// package types get from their embedded fields.
+func collectPromotedMethods(u *unit, p *xast.Package) {
+	scope := p.TypPkg.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		typ, ok := obj.Type().(*types.Named)
+		if !ok || !xtypes.IsStruct(typ) || typ.TypeParams().Len() != 0 {
+			continue
+		}
+		mset := types.NewMethodSet(types.NewPointer(typ))
+		for i := 0; i < mset.Len(); i++ {
+			sel := mset.At(i)
+			if len(sel.Index()) == 1 {
+				continue // Declared by the type itself
+			}
+			if fn := u.conv.PromotedMethod(p, typ, sel); fn != nil {
+				u.ins.Method(obj, sel.Obj().Name(), fn)
+			}
+		}
+	}
 }
 
 func parseFuncDocText(fn *sexp.Func, doc *ast.CommentGroup) string {
@@ -157,32 +255,79 @@ func collectFunc(u *unit, p *xast.Package, decl *ast.FuncDecl) {
 	sig := declSignature(p.Info, decl)
 	name := decl.Name.Name
 	fn := &sexp.Func{
-		Variadic: sig.Variadic(),
-		Results:  resultTuple(sig),
+		Results: resultTuple(sig),
 	}
 	fn.DocString = parseFuncDocText(fn, decl.Doc)
-	if recv := sig.Recv(); recv == nil {
+	declName := name
+	if sig.TypeParams().Len() != 0 || sig.RecvTypeParams().Len() != 0 {
+		// Generic functions are converted for every
+		// instantiation that is used (see convertInstances).
+		collectGeneric(u, p, decl, sig, fn.DocString)
+		return
+	}
+	if recv := sig.Recv(); recv == nil && name == "init" {
+		// Package may have many "init" functions.
+		// They can not be referenced, so they are
+		// not inserted into function table by name.
+		fn.Name = symbols.ManglePriv(p.FullName, fmt.Sprintf("init.%d", len(u.inits[p])))
+		u.ins.Lambda(p.TypPkg, fn)
+		u.inits[p] = append(u.inits[p], fn)
+	} else if recv == nil {
 		// Function.
 		fn.Params = make([]string, 0, decl.Type.Params.NumFields())
 		fn.Name = symbols.Mangle(p.FullName, name)
 		fillFuncParamsInfo(u, fn, sig)
 		u.ins.Func(p.TypPkg, name, fn)
+		if hasVariadicEntry(p, name, sig) {
+			entry := sexpconv.VariadicEntry(fn, sig)
+			fn.Name = symbols.ManglePriv(p.FullName, name)
+			u.ins.Lambda(p.TypPkg, entry)
+		}
 	} else {
 		// Method.
 		fn.Params = make([]string, 0, decl.Type.Params.NumFields()+1)
 		fn.Params = append(fn.Params, recv.Name()) // "recv" param
 		typ := getRecvType(recv)
 		fn.Name = symbols.MangleMethod(p.FullName, typ.Name(), name)
+		declName = typ.Name() + "." + name
 		fillFuncParamsInfo(u, fn, sig)
 		u.ins.Method(typ, name, fn)
 	}
 	u.decls[fn] = funcDeclData{
 		decl: decl,
 		pkg:  p,
+		name: declName,
+		sig:  sig,
 	}
 }
 
+// hasVariadicEntry reports whether function needs an
+// exported entry point that takes "&rest" argument.
+// Runtime functions are called by generated code only.
+func hasVariadicEntry(p *xast.Package, name string, sig *types.Signature) bool {
+	return sig.Variadic() && ast.IsExported(name) && p.TypPkg.Path() != "emacs/rt"
+}
+
+func collectGeneric(u *unit, p *xast.Package, decl *ast.FuncDecl, sig *types.Signature, doc string) {
+	name := decl.Name.Name
+	if recv := sig.Recv(); recv != nil {
+		typ := getRecvType(recv)
+		name = typ.Name() + "." + name
+	}
+	u.generics[symbols.Mangle(p.FullName, name)] = funcDeclData{
+		decl: decl,
+		pkg:  p,
+		name: name,
+		sig:  sig,
+		doc:  doc,
+	}
+}
+
+// fillFuncParamsInfo appends sig params to fn.Params.
+// Method receiver must be already appended;
+// InterfaceInputs are indexed by argument position.
 func fillFuncParamsInfo(u *unit, fn *sexp.Func, sig *types.Signature) {
+	offset := len(fn.Params)
 	for i := 0; i < sig.Params().Len(); i++ {
 		param := sig.Params().At(i)
 		fn.Params = append(fn.Params, param.Name())
@@ -191,7 +336,7 @@ func fillFuncParamsInfo(u *unit, fn *sexp.Func, sig *types.Signature) {
 			if fn.InterfaceInputs == nil {
 				fn.InterfaceInputs = make(map[int]types.Type, sig.Params().Len()-i)
 			}
-			fn.InterfaceInputs[i] = param.Type()
+			fn.InterfaceInputs[offset+i] = param.Type()
 		}
 	}
 }
@@ -202,27 +347,22 @@ func collectInitializers(u *unit, p *xast.Package) initData {
 	vars := make([]string, 0, 8)
 	env := conv.Env()
 
-	// Initialize master package itabs.
-	for _, itab := range u.itabEnv.GetMasterItabs() {
-		vars = append(vars, itab.Name)
-		iface := itab.Iface
-		elems := make([]sexp.Form, iface.NumMethods()+1)
-		elems[0] = sexp.Symbol{Val: p.FullName + "." + itab.ImplName}
-		for i := 0; i < iface.NumMethods(); i++ {
-			sym := symbols.MangleMethod(
-				p.FullName,
-				itab.ImplName,
-				iface.Method(i).Name(),
-			)
-			elems[i+1] = sexp.Symbol{Val: sym}
+	// InitOrder misses entries for variables without explicit
+	// initializers. They are zero initialized before any
+	// initializer expression is evaluated.
+	explicit := make(map[*types.Var]bool)
+	for _, init := range p.InitOrder {
+		for _, v := range init.Lhs {
+			explicit[v] = true
+		}
+	}
+	topScope := p.TypPkg.Scope()
+	for _, name := range topScope.Names() {
+		if v, ok := topScope.Lookup(name).(*types.Var); ok && !explicit[v] {
+			sym := env.InternVar(nil, v.Name())
+			vars = append(vars, sym)
+			body = append(body, conv.VarZeroInit(sym, v.Type()))
 		}
-		body = append(body, &sexp.VarUpdate{
-			Name: itab.Name,
-			Expr: sexp.NewLispCall(
-				lisp.FnVector,
-				elems...,
-			),
-		})
 	}
 
 	blankIdent := &ast.Ident{Name: "_"}
@@ -250,31 +390,153 @@ func collectInitializers(u *unit, p *xast.Package) initData {
 		}
 	}
 
-	// InitOrder misses entries for variables without explicit
-	// initializers. They are collected here.
-	topScope := p.TypPkg.Scope()
-	for _, name := range topScope.Names() {
-		if v, ok := topScope.Lookup(name).(*types.Var); ok {
-			if env.ContainsVar(v.Name()) {
-				continue
+	// Itabs can be interned during initializers conversion,
+	// so they are collected last, but initialized first.
+	itabVars, itabInits := collectItabs(u, p)
+	vars = append(itabVars, vars...)
+	body = append(itabInits, body...)
+
+	// "init" functions are called after all variables
+	// are initialized.
+	for _, fn := range u.inits[p] {
+		body = append(body, &sexp.ExprStmt{Expr: sexp.NewCall(fn)})
+	}
+
+	// Imported packages are initialized before importing package.
+	// Init function of every package runs only once, which is
+	// guarded by "initialized" variable.
+	initialized := sexp.Var{
+		Name: symbols.ManglePriv(p.FullName, "initialized"),
+		Typ:  xtypes.TypBool,
+	}
+	prologue := []sexp.Form{
+		&sexp.If{
+			Cond: initialized,
+			Then: sexp.Block{&sexp.Return{}},
+			Else: sexp.EmptyForm,
+		},
+		&sexp.VarUpdate{Name: initialized.Name, Expr: sexp.Bool(true)},
+	}
+	for _, imp := range p.TypPkg.Imports() {
+		if imp == lisp.Package {
+			continue
+		}
+		fn := &sexp.Func{
+			Name:    initFuncName(pkgFullName(imp.Path())),
+			Results: xtypes.EmptyTuple,
+		}
+		prologue = append(prologue, &sexp.ExprStmt{Expr: sexp.NewCall(fn)})
+	}
+	body = append(prologue, body...)
+	body = append(body, &sexp.Return{})
+
+	init := &sexp.Func{
+		Name:    initFuncName(p.FullName),
+		Body:    sexp.Block(body),
+		Results: xtypes.EmptyTuple,
+	}
+	// Package initialization is performed every time
+	// package is loaded, so "initialized" flag is reset first.
+	load := &sexp.Func{
+		Name: "init",
+		Body: sexp.Block{
+			&sexp.VarUpdate{Name: initialized.Name, Expr: sexp.Bool(false)},
+			&sexp.ExprStmt{Expr: sexp.NewCall(init)},
+			&sexp.Return{},
+		},
+	}
+	return initData{
+		init: init,
+		load: load,
+		vars: append([]string{initialized.Name}, vars...),
+	}
+}
+
+// initFuncName returns package initialization function name.
+func initFuncName(pkgName string) string {
+	return symbols.ManglePriv(pkgName, "init")
+}
+
+// collectItabs returns master package itab and interface descriptor
+// variables along with their initializers.
+func collectItabs(u *unit, p *xast.Package) ([]string, []sexp.Form) {
+	var vars []string
+	var body []sexp.Form
+
+	// Initialize master package itabs.
+	sigsDone := make(map[string]bool)
+	for _, itab := range u.itabEnv.GetMasterItabs() {
+		vars = append(vars, itab.Name)
+		iface := itab.Iface
+		// Generic type instances may be declared by imported package.
+		implPkg := p.FullName
+		for _, imp := range u.pkgs {
+			if imp.TypPkg.Path() == itab.ImplPkg.Path() {
+				implPkg = imp.FullName
 			}
-			sym := env.InternVar(nil, v.Name())
-			vars = append(vars, sym)
-			body = append(body, conv.VarZeroInit(sym, v.Type()))
 		}
+		if tag := symbols.TypeTag(implPkg, itab.ImplName, false); !sigsDone[tag] {
+			sigsDone[tag] = true
+			body = append(body, methodSigs(implPkg, itab)...)
+		}
+		elems := make([]sexp.Form, iface.NumMethods()+1)
+		elems[0] = sexp.Symbol{Val: symbols.TypeTag(implPkg, itab.ImplName, itab.ImplPtr)}
+		for i := 0; i < iface.NumMethods(); i++ {
+			sym := symbols.MangleMethod(
+				implPkg,
+				itab.ImplName,
+				iface.Method(i).Name(),
+			)
+			elems[i+1] = sexp.Symbol{Val: sym}
+		}
+		body = append(body, &sexp.VarUpdate{
+			Name: itab.Name,
+			Expr: sexp.NewLispCall(
+				lisp.FnVector,
+				elems...,
+			),
+		})
 	}
 
-	if len(body) != 0 {
-		body = append(body, &sexp.Return{})
+	// Initialize master package interface descriptors.
+	for _, iface := range u.itabEnv.GetMasterIfaces() {
+		vars = append(vars, iface.Name)
+		elems := make([]sexp.Form, iface.Iface.NumMethods()+1)
+		elems[0] = sexp.Str(iface.TypeName)
+		for i := 0; i < iface.Iface.NumMethods(); i++ {
+			method := iface.Iface.Method(i)
+			elems[i+1] = sexp.NewLispCall(
+				lisp.FnCons,
+				sexp.Str(method.Name()),
+				sexp.Int(symbols.SigHash(method.Type().(*types.Signature))),
+			)
+		}
+		body = append(body, &sexp.VarUpdate{
+			Name: iface.Name,
+			Expr: sexp.NewLispCall(lisp.FnVector, elems...),
+		})
 	}
 
-	return initData{
-		init: &sexp.Func{
-			Name: "init",
-			Body: sexp.Block(body),
-		},
-		vars: vars,
+	return vars, body
+}
+
+// methodSigs returns forms that store signature hashes of
+// itab implementation type methods as "goism-sig" property.
+// Run time itabs are built only from methods with matching signatures.
+func methodSigs(implPkg string, itab symbols.Itab) []sexp.Form {
+	mset := types.NewMethodSet(types.NewPointer(itab.Impl))
+	forms := make([]sexp.Form, mset.Len())
+	for i := range forms {
+		method := mset.At(i).Obj()
+		sym := symbols.MangleMethod(implPkg, itab.ImplName, method.Name())
+		forms[i] = &sexp.ExprStmt{Expr: sexp.NewLispCall(
+			lisp.InternFunc("put"),
+			sexp.Symbol{Val: sym},
+			sexp.Symbol{Val: "goism-sig"},
+			sexp.Int(symbols.SigHash(method.Type().(*types.Signature))),
+		)}
 	}
+	return forms
 }
 
 func collectImportsIter(pkgs *[]*xast.Package, p *xast.Package) error {
@@ -303,8 +565,8 @@ func collectImports(u *unit, p *xast.Package) error {
 	return err
 }
 
-func translatePkg(pkgPath string) (*xast.Package, error) {
-	pkgPath = build.Default.GOPATH + "/src/" + pkgPath
+func translatePkg(importPath string) (*xast.Package, error) {
+	pkgPath := build.Default.GOPATH + "/src/" + importPath
 	fset := token.NewFileSet()
 	astPkg, err := parseDir(fset, pkgPath, parser.ParseComments)
 	if err != nil {
@@ -315,20 +577,43 @@ func translatePkg(pkgPath string) (*xast.Package, error) {
 		Defs:       make(map[*ast.Ident]types.Object),
 		Uses:       make(map[*ast.Ident]types.Object),
 		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Implicits:  make(map[ast.Node]types.Object),
+		Instances:  make(map[*ast.Ident]types.Instance),
 	}
-	typPkg, err := typecheckPkg(fset, astPkg, ti)
+	typPkg, err := typecheckPkg(fset, importPath, astPkg, ti)
 	if err != nil {
 		return nil, err
 	}
 	return &xast.Package{
-		AstPkg:   astPkg,
-		TypPkg:   typPkg,
-		Info:     ti,
-		FileSet:  fset,
-		FullName: pkgFullName(pkgPath),
+		AstPkg:     astPkg,
+		TypPkg:     typPkg,
+		Info:       ti,
+		FileSet:    fset,
+		FullName:   pkgFullName(pkgPath),
+		Wraparound: parsePkgDirectives(astPkg),
 	}, nil
 }
 
+// parsePkgDirectives reports whether package comment
+// contains "//goism:wraparound" directive.
+// Directive lines are cleared, so they do not
+// appear inside package documentation.
+func parsePkgDirectives(pkg *ast.Package) bool {
+	wraparound := false
+	for _, f := range pkg.Files {
+		if f.Doc == nil {
+			continue
+		}
+		for _, line := range f.Doc.List {
+			if line.Text == "//goism:wraparound" {
+				wraparound = true
+				line.Text = "//" // Clear comment line
+			}
+		}
+	}
+	return wraparound
+}
+
 func pkgFullName(pkgPath string) string {
 	offset := strings.Index(pkgPath, "emacs/") + len("emacs/")
 	return pkgPath[offset:]
@@ -354,13 +639,27 @@ var typecheckCfg = types.Config{
 	Importer: &emacsImporter{impl: importer.Default()},
 }
 
-func typecheckPkg(fset *token.FileSet, pkg *ast.Package, ti *types.Info) (*types.Package, error) {
-	// Convert file map to slice.
-	files := make([]*ast.File, 0, len(pkg.Files))
-	for _, file := range pkg.Files {
-		files = append(files, file)
+// typecheckPkg checks package with its import path, so objects
+// that package refers to itself have the same Path as objects
+// referenced by its importers.
+func typecheckPkg(fset *token.FileSet, importPath string, pkg *ast.Package, ti *types.Info) (*types.Package, error) {
+	return typecheckCfg.Check(importPath, fset, sortedFiles(pkg), ti)
+}
+
+// sortedFiles returns package files sorted by their names.
+// Go spec requires initialization to be performed
+// in the order files are presented to the compiler.
+func sortedFiles(pkg *ast.Package) []*ast.File {
+	names := make([]string, 0, len(pkg.Files))
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	files := make([]*ast.File, len(names))
+	for i, name := range names {
+		files[i] = pkg.Files[name]
 	}
-	return typecheckCfg.Check(pkg.Name, fset, files, ti)
+	return files
 }
 
 func pkgComment(files map[string]*ast.File) string {
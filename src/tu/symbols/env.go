@@ -26,11 +26,6 @@ func NewEnv(pkgPath string) *Env {
 	}
 }
 
-func (env *Env) ContainsVar(name string) bool {
-	_, ok := env.symbols[name]
-	return ok
-}
-
 func (env *Env) internVar(bucket map[string]string, pkgPath string, name string) string {
 	if sym := bucket[name]; sym != "" {
 		return sym
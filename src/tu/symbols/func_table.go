@@ -15,13 +15,17 @@ type FuncTable struct {
 	externFuncs map[funcKey]*sexp.Func
 }
 
+// Imported packages are type checked separately, so
+// the same package may be represented by different *types.Package
+// objects. Package path is used as a key instead; unlike
+// package name, it is unique.
 type funcKey struct {
-	pkg  *types.Package
-	name string
+	pkgPath string
+	name    string
 }
 
 type methodKey struct {
-	pkgName  string
+	pkgPath  string
 	typeName string
 	name     string
 }
@@ -52,13 +56,13 @@ func (ftab *FuncTable) LookupFunc(p *types.Package, name string) *sexp.Func {
 	if p == ftab.masterPkg {
 		return ftab.funcs[name]
 	}
-	return ftab.externFuncs[funcKey{pkg: p, name: name}]
+	return ftab.externFuncs[funcKey{pkgPath: p.Path(), name: name}]
 }
 
 // LookupMethod returns stored method or nil if no entry is found.
 func (ftab *FuncTable) LookupMethod(recv *types.TypeName, name string) *sexp.Func {
 	key := methodKey{
-		pkgName:  recv.Pkg().Name(),
+		pkgPath:  recv.Pkg().Path(),
 		typeName: recv.Name(),
 		name:     name,
 	}
@@ -79,7 +83,7 @@ func (ins *FuncTableInserter) Func(p *types.Package, name string, fn *sexp.Func)
 		ins.ftab.funcs[name] = fn
 		ins.masterFuncs = append(ins.masterFuncs, fn)
 	} else {
-		ins.ftab.externFuncs[funcKey{pkg: p, name: name}] = fn
+		ins.ftab.externFuncs[funcKey{pkgPath: p.Path(), name: name}] = fn
 		ins.otherFuncs = append(ins.otherFuncs, fn)
 	}
 }
@@ -87,12 +91,22 @@ func (ins *FuncTableInserter) Func(p *types.Package, name string, fn *sexp.Func)
 // Method inserts a new method into table.
 func (ins *FuncTableInserter) Method(recv *types.TypeName, name string, fn *sexp.Func) {
 	key := methodKey{
-		pkgName:  recv.Pkg().Name(),
+		pkgPath:  recv.Pkg().Path(),
 		typeName: recv.Name(),
 		name:     name,
 	}
 	ins.ftab.methods[key] = fn
-	if key.pkgName == ins.ftab.masterPkg.Name() {
+	if key.pkgPath == ins.ftab.masterPkg.Path() {
+		ins.masterFuncs = append(ins.masterFuncs, fn)
+	} else {
+		ins.otherFuncs = append(ins.otherFuncs, fn)
+	}
+}
+
+// Lambda inserts a function that is created from function literal.
+// Such functions can not be looked up by name.
+func (ins *FuncTableInserter) Lambda(p *types.Package, fn *sexp.Func) {
+	if p == ins.ftab.masterPkg {
 		ins.masterFuncs = append(ins.masterFuncs, fn)
 	} else {
 		ins.otherFuncs = append(ins.otherFuncs, fn)
@@ -108,5 +122,7 @@ func (ins *FuncTableInserter) GetMasterFuncs() []*sexp.Func {
 // GetAllFuncs returns all functons ever inserted into function table.
 // Returned slice elements are sorted with in-source declaration order.
 func (ins *FuncTableInserter) GetAllFuncs() []*sexp.Func {
-	return append(ins.otherFuncs, ins.masterFuncs...)
+	funcs := make([]*sexp.Func, 0, len(ins.otherFuncs)+len(ins.masterFuncs))
+	funcs = append(funcs, ins.otherFuncs...)
+	return append(funcs, ins.masterFuncs...)
 }
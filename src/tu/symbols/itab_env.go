@@ -2,6 +2,11 @@ package symbols
 
 import (
 	"go/types"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"xtypes"
 )
 
 // ItabEnv used to store interface dynamic type info.
@@ -9,7 +14,11 @@ type ItabEnv struct {
 	masterPkg *types.Package
 
 	vals        map[itabKey]string
+	interned    map[string]bool
 	masterItabs []Itab
+
+	ifaces       map[*types.Named]string
+	masterIfaces []Iface
 }
 
 type typeInfo struct {
@@ -19,13 +28,25 @@ type typeInfo struct {
 
 type itabKey struct {
 	impl  *types.Named
+	ptr   bool
 	iface *types.Named
 }
 
 // Itab contains information about interface table variable.
 type Itab struct {
 	Name     string // Symbol name
+	Impl     *types.Named
 	ImplName string // Implementation type name
+	ImplPkg  *types.Package
+	ImplPtr  bool // Implementation type is a pointer to named type
+	Iface    *types.Interface
+}
+
+// Iface contains information about interface descriptor variable.
+// Descriptors are used to build itabs during run time.
+type Iface struct {
+	Name     string // Symbol name
+	TypeName string // Qualified interface type name
 	Iface    *types.Interface
 }
 
@@ -33,27 +54,50 @@ func NewItabEnv(masterPkg *types.Package) *ItabEnv {
 	return &ItabEnv{
 		masterPkg: masterPkg,
 		vals:      make(map[itabKey]string, 32),
+		interned:  make(map[string]bool, 32),
+		ifaces:    make(map[*types.Named]string, 8),
 	}
 }
 
 // Intern returns itab variable name.
-func (env *ItabEnv) Intern(implTyp, ifaceTyp *types.Named) string {
-	key := itabKey{impl: implTyp, iface: ifaceTyp}
+// Implementation type is either named type or a pointer to it.
+// Nil ifaceTyp stands for "interface{}"; such itabs
+// hold only the dynamic type tag.
+func (env *ItabEnv) Intern(typ types.Type, ifaceTyp *types.Named) string {
+	_, ptr := typ.(*types.Pointer)
+	implTyp := xtypes.AsNamedType(typ)
+	key := itabKey{impl: implTyp, ptr: ptr, iface: ifaceTyp}
 	if val := env.vals[key]; val != "" {
 		return val
 	}
-	implObj, ifaceObj := implTyp.Obj(), ifaceTyp.Obj()
-	implStr := implObj.Name()
-	ifaceStr := ifaceObj.Pkg().Name() + "." + ifaceObj.Name()
+	implObj := implTyp.Obj()
+	implStr := NamedTypeName(implTyp)
+	iface := types.NewInterfaceType(nil, nil)
+	ifaceStr := "any"
+	if ifaceTyp != nil {
+		iface = ifaceTyp.Underlying().(*types.Interface)
+		ifaceStr = qualifiedTypeName(ifaceTyp)
+	}
 	name := "%itab/" + implStr + "/" + ifaceStr
-	// #FIXME: should have full package path here instead of Pkg().Name().
-	sym := ManglePriv(implObj.Pkg().Name(), name)
+	if ptr {
+		name = "%itab/*" + implStr + "/" + ifaceStr
+	}
+	sym := ManglePriv(pkgFullName(implObj.Pkg().Path()), name)
 	env.vals[key] = sym
-	if implObj.Pkg() == env.masterPkg {
+	// Equal generic type instances may be represented by different objects.
+	if env.interned[sym] {
+		return sym
+	}
+	env.interned[sym] = true
+	// Generic type instances are defined by every package that uses them.
+	if implObj.Pkg() == env.masterPkg || implTyp.TypeArgs().Len() != 0 {
 		env.masterItabs = append(env.masterItabs, Itab{
 			Name:     sym,
-			Iface:    ifaceTyp.Underlying().(*types.Interface),
-			ImplName: implObj.Name(),
+			Iface:    iface,
+			Impl:     implTyp,
+			ImplName: implStr,
+			ImplPkg:  implObj.Pkg(),
+			ImplPtr:  ptr,
 		})
 	}
 	return sym
@@ -62,3 +106,187 @@ func (env *ItabEnv) Intern(implTyp, ifaceTyp *types.Named) string {
 func (env *ItabEnv) GetMasterItabs() []Itab {
 	return env.masterItabs
 }
+
+// InternIface returns interface descriptor variable name.
+// Descriptors are defined inside master package.
+func (env *ItabEnv) InternIface(ifaceTyp *types.Named) string {
+	if val := env.ifaces[ifaceTyp]; val != "" {
+		return val
+	}
+	typeName := qualifiedTypeName(ifaceTyp)
+	sym := ManglePriv(pkgFullName(env.masterPkg.Path()), "%iface/"+typeName)
+	env.ifaces[ifaceTyp] = sym
+	env.masterIfaces = append(env.masterIfaces, Iface{
+		Name:     sym,
+		TypeName: typeName,
+		Iface:    ifaceTyp.Underlying().(*types.Interface),
+	})
+	return sym
+}
+
+func (env *ItabEnv) GetMasterIfaces() []Iface {
+	return env.masterIfaces
+}
+
+// TypeName returns type name that is unique inside its package.
+// Types that are declared inside functions get suffix that
+// consists of enclosing function name and ordinal of the type
+// among local types of that function, in source order.
+// TypeName(T) => "T"; TypeName(T local to f) => "T/f.1".
+func TypeName(obj *types.TypeName) string {
+	if obj.Parent() == nil || obj.Parent() == obj.Pkg().Scope() {
+		return obj.Name()
+	}
+	owner, roots := localTypeOwner(obj)
+	var decls []*types.TypeName
+	for _, root := range roots {
+		decls = collectLocalTypes(decls, root)
+	}
+	sort.Slice(decls, func(i, j int) bool {
+		return decls[i].Pos() < decls[j].Pos()
+	})
+	ordinal := 0
+	for i, decl := range decls {
+		if decl == obj {
+			ordinal = i + 1
+			break
+		}
+	}
+	return obj.Name() + "/" + owner + "." + strconv.Itoa(ordinal)
+}
+
+// localTypeOwner finds function that encloses local type declaration.
+// Returns function name and scopes that share local type ordinals.
+// Methods are named "T.m"; all function literals
+// of package level initializers share "func" owner.
+func localTypeOwner(obj *types.TypeName) (string, []*types.Scope) {
+	pkgScope := obj.Pkg().Scope()
+	top := obj.Parent()
+	for top.Parent() != nil && top.Parent().Parent() != pkgScope {
+		top = top.Parent()
+	}
+	named := make(map[*types.Scope]bool)
+	for _, name := range pkgScope.Names() {
+		switch obj := pkgScope.Lookup(name).(type) {
+		case *types.Func:
+			if obj.Scope() == top {
+				return obj.Name(), []*types.Scope{top}
+			}
+			named[obj.Scope()] = true
+		case *types.TypeName:
+			typ, ok := obj.Type().(*types.Named)
+			if !ok {
+				continue
+			}
+			for i := 0; i < typ.NumMethods(); i++ {
+				method := typ.Method(i)
+				if method.Scope() == top {
+					return typ.Obj().Name() + "." + method.Name(), []*types.Scope{top}
+				}
+				named[method.Scope()] = true
+			}
+		}
+	}
+	var roots []*types.Scope
+	for i := 0; i < pkgScope.NumChildren(); i++ {
+		file := pkgScope.Child(i)
+		for j := 0; j < file.NumChildren(); j++ {
+			if scope := file.Child(j); !named[scope] {
+				roots = append(roots, scope)
+			}
+		}
+	}
+	return "func", roots
+}
+
+// collectLocalTypes appends type declarations of scope tree to decls.
+func collectLocalTypes(decls []*types.TypeName, scope *types.Scope) []*types.TypeName {
+	for _, name := range scope.Names() {
+		if decl, ok := scope.Lookup(name).(*types.TypeName); ok {
+			decls = append(decls, decl)
+		}
+	}
+	for i := 0; i < scope.NumChildren(); i++ {
+		decls = collectLocalTypes(decls, scope.Child(i))
+	}
+	return decls
+}
+
+// NamedTypeName is like TypeName, but it also includes
+// type arguments of generic type instances.
+// NamedTypeName(Pair[int, string]) => "Pair[int,string]".
+func NamedTypeName(typ *types.Named) string {
+	args := make([]types.Type, typ.TypeArgs().Len())
+	for i := range args {
+		args[i] = typ.TypeArgs().At(i)
+	}
+	return InstanceName(TypeName(typ.Obj()), args)
+}
+
+// InstanceName returns generic function or type instance name.
+// Named type arguments are qualified by their package full name.
+// InstanceName("Map", [int, pkg.T]) => "Map[int,pkg.T]".
+func InstanceName(name string, args []types.Type) string {
+	if len(args) == 0 {
+		return name
+	}
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		strs[i] = typeArgString(arg)
+	}
+	return name + "[" + strings.Join(strs, ",") + "]"
+}
+
+// qualifiedTypeName returns NamedTypeName prefixed by package full name,
+// so types of different packages with the same name do not collide.
+// Predeclared types (like "error") are not qualified.
+func qualifiedTypeName(typ *types.Named) string {
+	if pkg := typ.Obj().Pkg(); pkg != nil {
+		return pkgFullName(pkg.Path()) + "." + NamedTypeName(typ)
+	}
+	return NamedTypeName(typ)
+}
+
+func typeArgString(typ types.Type) string {
+	if named, ok := types.Unalias(typ).(*types.Named); ok {
+		return qualifiedTypeName(named)
+	}
+	return types.TypeString(typ, (*types.Package).Name)
+}
+
+// TypeTag returns a symbol name that identifies dynamic type.
+// It is stored as the first itab element.
+// Pointer types get "*" prefix.
+// TypeTag("pkg", "T", false) => "pkg.T";
+// TypeTag("pkg", "T", true) => "*pkg.T".
+func TypeTag(pkgPath string, typName string, ptr bool) string {
+	if ptr {
+		return "*" + pkgPath + "." + typName
+	}
+	return pkgPath + "." + typName
+}
+
+// SigHash returns a hash of method signature; receiver is ignored.
+// Interface descriptors keep it for every method, so itabs
+// that are built during run time match methods by signature,
+// not only by name.
+func SigHash(sig *types.Signature) int {
+	sig = types.NewSignatureType(
+		nil, nil, nil,
+		unnamedTuple(sig.Params()), unnamedTuple(sig.Results()),
+		sig.Variadic(),
+	)
+	h := fnv.New32a()
+	h.Write([]byte(types.TypeString(sig, (*types.Package).Path)))
+	return int(h.Sum32() & 0xFFFFFFF) // Fits into Emacs fixnum
+}
+
+// unnamedTuple returns tuple with the same types, but without names,
+// so parameter names do not affect the signature string.
+func unnamedTuple(tuple *types.Tuple) *types.Tuple {
+	vars := make([]*types.Var, tuple.Len())
+	for i := range vars {
+		vars[i] = types.NewParam(0, nil, "", tuple.At(i).Type())
+	}
+	return types.NewTuple(vars...)
+}
@@ -23,6 +23,7 @@ var instrNameToCost = map[string]int{
 	"cons":      2,
 	"car":       1,
 	"cdr":       1,
+	"setcar":    1,
 	"aref":      1,
 	"aset":      1,
 	"=":         1,
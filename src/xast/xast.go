@@ -15,6 +15,10 @@ type Package struct {
 	*types.Info
 	FileSet  *token.FileSet
 	FullName string
+
+	// Wraparound is true for packages that request exact
+	// integer overflow semantics ("//goism:wraparound").
+	Wraparound bool
 }
 
 // Assign carries information that is needed to
@@ -29,8 +33,15 @@ type Assign struct {
 // compile a function.
 type Func struct {
 	Pkg  *Package
+	Name string // Unqualified name; "T.name" for methods
+	Sig  *types.Signature
 	Ret  *types.Tuple
 	Body *ast.BlockStmt
+
+	// Generic function instance type arguments.
+	// Sig and Ret are substituted during conversion.
+	TypeParams *types.TypeParamList
+	TypeArgs   []types.Type
 }
 
 // ExprSlice converts any []T to []ast.Expr,
@@ -16,10 +16,17 @@ func IsStruct(typ types.Type) bool {
 	return ok
 }
 
+// IsEmptyInterface returns true for unnamed "interface{}" type.
+func IsEmptyInterface(typ types.Type) bool {
+	iface, ok := types.Unalias(typ).(*types.Interface)
+	return ok && iface.NumMethods() == 0
+}
+
 // IsGlobal checks if given object belongs to a global scope.
 func IsGlobal(obj types.Object) bool {
 	objScope := obj.Parent()
 	// If parent scope is Universe, then object scope
 	// is Package => it is global.
-	return objScope.Parent() == types.Universe
+	// Objects without scope (like fields) are never global.
+	return objScope != nil && objScope.Parent() == types.Universe
 }
@@ -11,10 +11,10 @@ var (
 	TypBool = types.Typ[types.Bool]
 
 	TypUint   = types.Typ[types.Uint]
-	TypUint8  = types.Typ[types.Uint]
-	TypUint16 = types.Typ[types.Uint]
-	TypUint32 = types.Typ[types.Uint]
-	TypUint64 = types.Typ[types.Uint]
+	TypUint8  = types.Typ[types.Uint8]
+	TypUint16 = types.Typ[types.Uint16]
+	TypUint32 = types.Typ[types.Uint32]
+	TypUint64 = types.Typ[types.Uint64]
 
 	TypInt   = types.Typ[types.Int]
 	TypInt8  = types.Typ[types.Int8]